@@ -0,0 +1,244 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"linkchex/internal/history"
+)
+
+// defaultHistoryDB is the history database used when --db isn't given
+const defaultHistoryDB = "linkchex-history.db"
+
+// runHistoryCommand lists recorded runs, or one link's status across runs
+func runHistoryCommand(args []string) error {
+	fs := flag.NewFlagSet("history", flag.ExitOnError)
+	db := fs.String("db", defaultHistoryDB, "History database written by --history-db")
+	limit := fs.Int("runs", 20, "Number of most recent runs to list (0 = all)")
+	link := fs.String("link", "", "Show one link target's status in every run")
+	format := fs.String("format", "text", "Output format (text, json)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: linkchex history [--db file] [--runs N] [--link URL] [--format text|json]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	store, err := openHistory(*db)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	if *link != "" {
+		timeline, err := store.LinkTimeline(*link)
+		if err != nil {
+			return fmt.Errorf("failed to read link history: %w", err)
+		}
+		if *format == "json" {
+			return printJSON(timeline)
+		}
+		printTimeline(*link, timeline)
+		return nil
+	}
+
+	runs, err := store.Runs(*limit)
+	if err != nil {
+		return fmt.Errorf("failed to read runs: %w", err)
+	}
+	if *format == "json" {
+		return printJSON(runs)
+	}
+
+	if len(runs) == 0 {
+		fmt.Println("No runs recorded yet")
+		return nil
+	}
+	fmt.Printf("%-6s %-20s %10s %7s %8s %8s %9s\n", "Run", "Started", "Duration", "Pages", "Links", "Broken", "Warnings")
+	for _, run := range runs {
+		fmt.Printf("%-6d %-20s %10s %7d %8d %8d %9d\n",
+			run.ID, run.StartedAt.Format("2006-01-02 15:04:05"), run.Duration.Round(time.Millisecond),
+			run.Pages, run.TotalLinks, run.BrokenLinks, run.WarningLinks)
+	}
+	return nil
+}
+
+// printTimeline prints a link target's status in each run it was checked in
+func printTimeline(target string, timeline []history.Observation) {
+	if len(timeline) == 0 {
+		fmt.Printf("No recorded results for %s\n", target)
+		return
+	}
+
+	fmt.Printf("History for %s\n\n", target)
+	var firstBroken, lastOK time.Time
+	for i, obs := range timeline {
+		state := "ok"
+		if obs.Broken {
+			state = "BROKEN"
+			if firstBroken.IsZero() {
+				firstBroken = obs.At
+			}
+		} else {
+			lastOK = obs.At
+		}
+
+		change := ""
+		if i > 0 && obs.Broken != timeline[i-1].Broken {
+			change = "  <- changed"
+		}
+		fmt.Printf("  run %-5d %s  %-6s  %s%s\n", obs.RunID, obs.At.Format("2006-01-02 15:04"), state, observationStatus(obs), change)
+	}
+
+	fmt.Println()
+	if firstBroken.IsZero() {
+		fmt.Println("Never broken")
+		return
+	}
+	fmt.Printf("First broken: %s\n", firstBroken.Format("2006-01-02 15:04"))
+	if last := timeline[len(timeline)-1]; last.Broken {
+		if lastOK.IsZero() {
+			fmt.Printf("Broken in every recorded run (%s)\n", formatAge(last.At.Sub(firstBroken)))
+		} else {
+			fmt.Printf("Last worked:  %s (broken for %s)\n", lastOK.Format("2006-01-02 15:04"), formatAge(last.At.Sub(lastOK)))
+		}
+	}
+}
+
+// trendsOutput is the JSON form of `linkchex trends`
+type trendsOutput struct {
+	Runs   []history.Run        `json:"runs"`
+	Broken []history.BrokenLink `json:"broken"`
+	Flaky  []history.FlakyLink  `json:"flaky"`
+}
+
+// runTrendsCommand shows broken-link counts over time, how long current
+// breakages have lasted and links that flip between ok and broken
+func runTrendsCommand(args []string) error {
+	fs := flag.NewFlagSet("trends", flag.ExitOnError)
+	db := fs.String("db", defaultHistoryDB, "History database written by --history-db")
+	window := fs.Int("runs", 30, "Number of most recent runs to analyze for counts and flaky links")
+	minFlips := fs.Int("min-flips", 2, "Changes between ok and broken needed to call a link flaky")
+	format := fs.String("format", "text", "Output format (text, json)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: linkchex trends [--db file] [--runs N] [--min-flips N] [--format text|json]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	store, err := openHistory(*db)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	runs, err := store.Runs(*window)
+	if err != nil {
+		return fmt.Errorf("failed to read runs: %w", err)
+	}
+	broken, err := store.BrokenLinks()
+	if err != nil {
+		return fmt.Errorf("failed to read broken links: %w", err)
+	}
+	flaky, err := store.FlakyLinks(*window, *minFlips)
+	if err != nil {
+		return fmt.Errorf("failed to read flaky links: %w", err)
+	}
+
+	if *format == "json" {
+		return printJSON(trendsOutput{Runs: runs, Broken: broken, Flaky: flaky})
+	}
+
+	if len(runs) == 0 {
+		fmt.Println("No runs recorded yet")
+		return nil
+	}
+
+	// Oldest first, scaled to the worst run
+	fmt.Printf("Broken Links Over Time (last %d runs)\n", len(runs))
+	fmt.Println("=====================================")
+	maxBroken := 0
+	for _, run := range runs {
+		maxBroken = max(maxBroken, run.BrokenLinks)
+	}
+	for i := len(runs) - 1; i >= 0; i-- {
+		run := runs[i]
+		bar := ""
+		if maxBroken > 0 {
+			bar = strings.Repeat("█", (run.BrokenLinks*40+maxBroken-1)/maxBroken)
+		}
+		fmt.Printf("  %s  %5d / %-6d %s\n", run.StartedAt.Format("2006-01-02 15:04"), run.BrokenLinks, run.TotalLinks, bar)
+	}
+
+	fmt.Printf("\nCurrently Broken (%d)\n", len(broken))
+	fmt.Println("====================")
+	for _, link := range broken {
+		fmt.Printf("  %s\n", link.TargetURL)
+		since := fmt.Sprintf("broken since %s (%s, %d run(s))", link.BrokenSince.Format("2006-01-02 15:04"), formatAge(link.BrokenFor), link.BrokenRuns)
+		if link.LastOK.IsZero() {
+			since += ", never worked"
+		} else if link.FirstBroken.Before(link.BrokenSince) {
+			since += fmt.Sprintf(", first broke %s", link.FirstBroken.Format("2006-01-02"))
+		}
+		fmt.Printf("    %s\n", since)
+		fmt.Printf("    %s, on %d page(s)\n", observationStatus(history.Observation{StatusCode: link.StatusCode, Status: link.Status, Error: link.Error}), link.SourcePages)
+	}
+
+	fmt.Printf("\nFlaky Links (%d)\n", len(flaky))
+	fmt.Println("================")
+	for _, link := range flaky {
+		state := "ok now"
+		if link.Last.Broken {
+			state = "broken now"
+		}
+		fmt.Printf("  %s\n    %d change(s), broken in %d of %d run(s), %s\n", link.TargetURL, link.Flips, link.BrokenRuns, link.Runs, state)
+	}
+	return nil
+}
+
+// openHistory opens an existing history database
+func openHistory(path string) (*history.Store, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("history database %s not found (record runs with --history-db)", path)
+	}
+	return history.Open(path)
+}
+
+// observationStatus describes a recorded result's status or error
+func observationStatus(obs history.Observation) string {
+	if obs.Error != "" {
+		return obs.Error
+	}
+	if obs.StatusCode == 0 {
+		return obs.Status
+	}
+	if strings.HasPrefix(obs.Status, fmt.Sprint(obs.StatusCode)) {
+		return obs.Status
+	}
+	return fmt.Sprintf("%d %s", obs.StatusCode, obs.Status)
+}
+
+// formatAge renders a duration in days and hours
+func formatAge(d time.Duration) string {
+	switch {
+	case d >= 24*time.Hour:
+		return fmt.Sprintf("%dd %dh", int(d.Hours())/24, int(d.Hours())%24)
+	case d >= time.Hour:
+		return fmt.Sprintf("%dh %dm", int(d.Hours()), int(d.Minutes())%60)
+	default:
+		return d.Round(time.Minute).String()
+	}
+}
+
+// printJSON writes a value as indented JSON to stdout
+func printJSON(v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(data))
+	return nil
+}
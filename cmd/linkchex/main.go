@@ -3,8 +3,17 @@ package main
 import (
 	"flag"
 	"fmt"
+	"net"
+	"net/http"
+	"net/url"
 	"os"
+	"strings"
+	"time"
 
+	"linkchex/internal/fetcher"
+	"linkchex/internal/history"
+	"linkchex/internal/metrics"
+	"linkchex/internal/notify"
 	"linkchex/internal/sitemap"
 	"linkchex/internal/validator"
 )
@@ -12,14 +21,33 @@ import (
 const version = "0.1.1"
 
 func main() {
+	// Subcommands query the history database instead of validating
+	if len(os.Args) > 1 {
+		var command func([]string) error
+		switch os.Args[1] {
+		case "history":
+			command = runHistoryCommand
+		case "trends":
+			command = runTrendsCommand
+		}
+		if command != nil {
+			if err := command(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
 	// Define CLI flags
 	url := flag.String("url", "", "Base URL to discover sitemap from")
-	sitemapURL := flag.String("sitemap", "", "Direct URL or path to sitemap file")
+	sitemapURL := flag.String("sitemap", "", "Direct URL or path to sitemap file (XML, text, RSS or Atom)")
+	urlsFile := flag.String("urls-file", "", "File with page URLs to validate, one per line ('-' for stdin)")
 	concurrency := flag.Int("concurrency", 200, "Number of concurrent workers")
 	verbose := flag.Bool("verbose", false, "Enable verbose output")
 	versionFlag := flag.Bool("version", false, "Show version information")
 	timeout := flag.Int("timeout", 10, "Request timeout in seconds")
-	format := flag.String("format", "text", "Output format (text, json, csv)")
+	format := flag.String("format", "text", "Output format (text, json, csv, markdown)")
 	output := flag.String("output", "", "Output file path (default: stdout)")
 	maxRetries := flag.Int("retries", 1, "Maximum number of retries for failed requests")
 	checkExternal := flag.Bool("check-external", true, "Check external links (default: internal only)")
@@ -28,7 +56,59 @@ func main() {
 	excludePattern := flag.String("exclude", "", "Exclude URLs matching pattern (supports * and ? wildcards)")
 	showProgress := flag.Bool("progress", false, "Show progress bar (auto-disabled with --verbose)")
 	skipResources := flag.Bool("skip-resources", false, "Skip checking <link> and <script> tags (check only <a> and <img>)")
+	skipRel := flag.String("skip-rel", "", "Comma-separated rel values whose links are skipped (e.g. nofollow,sponsored; 'download' skips download links)")
+	ignoreRobots := flag.Bool("ignore-robots", false, "Ignore robots.txt rules and Crawl-delay when fetching pages")
+	robotsLinks := flag.Bool("robots-links", false, "Also skip link targets disallowed by robots.txt")
+	auditSitemap := flag.Bool("audit-sitemap", false, "Audit sitemap entries (status, redirects, robots, noindex, canonical, duplicates, lastmod)")
+	checkHreflang := flag.Bool("check-hreflang", false, "Validate hreflang alternates from the sitemap and page <head> (reciprocity, x-default)")
+	checkMedia := flag.Bool("check-media", false, "Validate image and video URLs from image/video sitemap extensions")
+	sitemapDepth := flag.Int("sitemap-depth", sitemap.DefaultMaxDepth, "Maximum sitemap index nesting to follow")
+	soft404 := flag.Bool("soft404", false, "Detect soft 404s: <a> targets returning 200 that look like error pages")
+	soft404Title := flag.String("soft404-title", validator.DefaultSoft404TitlePattern, "Regex matched against page titles for soft 404 detection (empty to disable)")
+	soft404Body := flag.String("soft404-body", "", "Regex matched against page body text for soft 404 detection")
+	checkContent := flag.Bool("check-content", false, "Flag assets whose Content-Type doesn't match the tag (img, script, stylesheet) or that are empty")
+	maxImageKB := flag.Int64("max-image-kb", 0, "Flag images larger than this many KB (0 = no limit)")
+	maxScriptKB := flag.Int64("max-script-kb", 0, "Flag scripts larger than this many KB (0 = no limit)")
+	maxCSSKB := flag.Int64("max-css-kb", 0, "Flag stylesheets larger than this many KB (0 = no limit)")
+	securityAudit := flag.Bool("check-security", false, "Report mixed content, http links to https-capable hosts and https->http redirect downgrades")
+	checkPages := flag.Bool("check-pages", false, "Check crawled pages for missing/duplicate titles and descriptions, missing alt and lang, multiple h1, empty link text and unsafe target=_blank")
+	linkGraph := flag.Bool("link-graph", false, "Analyze the internal link graph: orphan pages, pages missing from the sitemap, click depth")
+	graphRoot := flag.String("graph-root", "", "Page click depth is measured from (default: homepage of the first URL)")
+	graphDOT := flag.String("graph-dot", "", "Write the internal link graph in Graphviz DOT format to this path")
+	graphML := flag.String("graph-graphml", "", "Write the internal link graph in GraphML format to this path")
+	render := flag.Bool("render", false, "Render pages in headless Chrome before extracting links (for JavaScript-built pages)")
+	chromePath := flag.String("chrome-path", "", "Chrome/Chromium binary for --render (default: search PATH)")
+	renderWait := flag.Duration("render-wait", 5*time.Second, "Longest to wait after load for JavaScript to finish adding links")
+	stripParams := flag.String("strip-params", strings.Join(fetcher.DefaultTrackingParams, ","), "Comma-separated query parameters ignored when deduplicating and caching links (trailing * matches a prefix, empty to keep all)")
+	foldSlash := flag.Bool("fold-trailing-slash", false, "Treat /path/ and /path as the same URL when deduplicating and caching links")
+	checkTLS := flag.Bool("check-tls", false, "Report TLS certificate details per https host and flag expiring, self-signed or mismatched certificates")
+	tlsExpiryDays := flag.Int("tls-expiry-days", 30, "Flag certificates expiring within this many days")
+	stateFile := flag.String("incremental", "", "State file for incremental runs: only re-crawl pages whose sitemap lastmod changed")
+	linkTTL := flag.Duration("link-ttl", 24*time.Hour, "How long stored working link results are reused in incremental mode (broken links are always re-checked)")
+	verifyBroken := flag.Bool("verify-broken", false, "Re-check broken links after the crawl and report the ones that now work as flaky instead of broken")
+	verifyDelay := flag.Duration("verify-delay", 5*time.Second, "How long to wait before re-checking broken links")
+	verifyGET := flag.Bool("verify-get", false, "Re-check broken links with GET instead of HEAD")
+	verifyTimeout := flag.Int("verify-timeout", 30, "Request timeout in seconds for re-checking broken links")
+	markdownLimit := flag.Int("markdown-limit", validator.DefaultMarkdownLimit, "Maximum size in bytes of the markdown report; page sections past it are omitted (0 = no limit)")
+	githubSummary := flag.Bool("github-summary", false, "Append a markdown report to $GITHUB_STEP_SUMMARY (GitHub Actions job summary)")
 	htmlOutput := flag.String("html", "", "Generate interactive HTML report at specified path (e.g., report.html)")
+	templateFile := flag.String("template", "", "Render the report through a Go template file instead of --format (*.html.tmpl uses html/template)")
+	metricsAddr := flag.String("metrics-addr", "", "Serve Prometheus metrics on this address at /metrics while the run is in progress (e.g., :9090)")
+	metricsFile := flag.String("metrics-file", "", "Write Prometheus metrics at the end of the run for node_exporter's textfile collector (e.g., /var/lib/node_exporter/linkchex.prom)")
+	historyDB := flag.String("history-db", "", "Record the run and every result in this SQLite database (see 'linkchex history' and 'linkchex trends')")
+	notifyWebhook := flag.String("notify-webhook", "", "Comma-separated webhook URLs to POST a JSON run summary to")
+	notifyTemplate := flag.String("notify-webhook-template", "", "Go template file for the --notify-webhook request body (JSON)")
+	notifySlack := flag.String("notify-slack", "", "Comma-separated Slack-compatible incoming webhook URLs")
+	notifyTeams := flag.String("notify-teams", "", "Comma-separated Microsoft Teams incoming webhook URLs")
+	notifyEmail := flag.String("notify-email", "", "Comma-separated email addresses to send the run summary to over SMTP")
+	smtpServer := flag.String("smtp-server", "localhost:25", "SMTP server (host:port) for --notify-email")
+	smtpFrom := flag.String("smtp-from", "linkchex@localhost", "Sender address for --notify-email")
+	smtpUser := flag.String("smtp-user", "", "SMTP username (password from $LINKCHEX_SMTP_PASSWORD)")
+	notifyOn := flag.String("notify-on", notify.OnBroken, "When to notify: always, broken (any broken links) or new (links broken since the last notification)")
+	notifyThrottle := flag.Duration("notify-throttle", 0, "Minimum time between notifications (e.g., 6h)")
+	notifyState := flag.String("notify-state", "", "File remembering notified links and the last notification, for --notify-on new and --notify-throttle")
+	notifyTop := flag.Int("notify-top", notify.DefaultTop, "Number of broken links listed in a notification")
+	notifyReportURL := flag.String("notify-report-url", "", "Link to the published report, included in notifications")
 
 	flag.Parse()
 
@@ -39,14 +119,21 @@ func main() {
 	}
 
 	// Validate input
-	if *url == "" && *sitemapURL == "" {
-		fmt.Fprintln(os.Stderr, "Error: Either --url or --sitemap must be provided")
+	sources := 0
+	for _, source := range []string{*url, *sitemapURL, *urlsFile} {
+		if source != "" {
+			sources++
+		}
+	}
+
+	if sources == 0 {
+		fmt.Fprintln(os.Stderr, "Error: One of --url, --sitemap or --urls-file must be provided")
 		flag.Usage()
 		os.Exit(1)
 	}
 
-	if *url != "" && *sitemapURL != "" {
-		fmt.Fprintln(os.Stderr, "Error: Cannot specify both --url and --sitemap")
+	if sources > 1 {
+		fmt.Fprintln(os.Stderr, "Error: Cannot specify more than one of --url, --sitemap and --urls-file")
 		flag.Usage()
 		os.Exit(1)
 	}
@@ -55,6 +142,7 @@ func main() {
 	config := &Config{
 		URL:            *url,
 		SitemapURL:     *sitemapURL,
+		URLsFile:       *urlsFile,
 		Concurrency:    *concurrency,
 		Verbose:        *verbose,
 		Timeout:        *timeout,
@@ -67,7 +155,59 @@ func main() {
 		ExcludePattern: *excludePattern,
 		ShowProgress:   *showProgress,
 		SkipResources:  *skipResources,
+		SkipRels:       splitList(*skipRel),
+		IgnoreRobots:   *ignoreRobots,
+		RobotsLinks:    *robotsLinks,
+		AuditSitemap:   *auditSitemap,
+		CheckHreflang:  *checkHreflang,
+		CheckMedia:     *checkMedia,
+		SitemapDepth:   *sitemapDepth,
+		Soft404:        *soft404,
+		Soft404Title:   *soft404Title,
+		Soft404Body:    *soft404Body,
+		CheckContent:   *checkContent,
+		MaxImageKB:     *maxImageKB,
+		MaxScriptKB:    *maxScriptKB,
+		MaxCSSKB:       *maxCSSKB,
+		SecurityAudit:  *securityAudit,
+		CheckPages:     *checkPages,
+		LinkGraph:      *linkGraph || *graphDOT != "" || *graphML != "",
+		GraphRoot:      *graphRoot,
+		GraphDOT:       *graphDOT,
+		GraphML:        *graphML,
+		Render:         *render,
+		ChromePath:     *chromePath,
+		RenderWait:     *renderWait,
+		StripParams:    splitList(*stripParams),
+		FoldSlash:      *foldSlash,
+		CheckTLS:       *checkTLS,
+		TLSExpiryDays:  *tlsExpiryDays,
+		StateFile:      *stateFile,
+		LinkTTL:        *linkTTL,
+		VerifyBroken:   *verifyBroken,
+		VerifyDelay:    *verifyDelay,
+		VerifyGET:      *verifyGET,
+		VerifyTimeout:  *verifyTimeout,
+		MarkdownLimit:  *markdownLimit,
+		GitHubSummary:  *githubSummary,
 		HTMLOutput:     *htmlOutput,
+		Template:       *templateFile,
+		MetricsAddr:    *metricsAddr,
+		MetricsFile:    *metricsFile,
+		HistoryDB:      *historyDB,
+		NotifyWebhooks: splitList(*notifyWebhook),
+		NotifyTemplate: *notifyTemplate,
+		NotifySlack:    splitList(*notifySlack),
+		NotifyTeams:    splitList(*notifyTeams),
+		NotifyEmail:    splitList(*notifyEmail),
+		SMTPServer:     *smtpServer,
+		SMTPFrom:       *smtpFrom,
+		SMTPUser:       *smtpUser,
+		NotifyOn:       *notifyOn,
+		NotifyThrottle: *notifyThrottle,
+		NotifyState:    *notifyState,
+		NotifyTop:      *notifyTop,
+		NotifyReport:   *notifyReportURL,
 	}
 
 	if err := run(config); err != nil {
@@ -79,6 +219,7 @@ func main() {
 type Config struct {
 	URL            string
 	SitemapURL     string
+	URLsFile       string
 	Concurrency    int
 	Verbose        bool
 	Timeout        int
@@ -91,57 +232,124 @@ type Config struct {
 	ExcludePattern string
 	ShowProgress   bool
 	SkipResources  bool
+	SkipRels       []string
+	IgnoreRobots   bool
+	RobotsLinks    bool
+	AuditSitemap   bool
+	CheckHreflang  bool
+	CheckMedia     bool
+	SitemapDepth   int
+	Soft404        bool
+	Soft404Title   string
+	Soft404Body    string
+	CheckContent   bool
+	MaxImageKB     int64
+	MaxScriptKB    int64
+	MaxCSSKB       int64
+	SecurityAudit  bool
+	CheckPages     bool
+	LinkGraph      bool
+	GraphRoot      string
+	GraphDOT       string
+	GraphML        string
+	Render         bool
+	ChromePath     string
+	RenderWait     time.Duration
+	StripParams    []string
+	FoldSlash      bool
+	CheckTLS       bool
+	TLSExpiryDays  int
+	StateFile      string
+	LinkTTL        time.Duration
+	VerifyBroken   bool
+	VerifyDelay    time.Duration
+	VerifyGET      bool
+	VerifyTimeout  int
+	MarkdownLimit  int
+	GitHubSummary  bool
 	HTMLOutput     string
+	Template       string
+	MetricsAddr    string
+	MetricsFile    string
+	HistoryDB      string
+	NotifyWebhooks []string `json:"-"` // Webhook URLs hold secrets, keep them out of --history-db
+	NotifyTemplate string
+	NotifySlack    []string `json:"-"`
+	NotifyTeams    []string `json:"-"`
+	NotifyEmail    []string
+	SMTPServer     string
+	SMTPFrom       string
+	SMTPUser       string
+	NotifyOn       string
+	NotifyThrottle time.Duration
+	NotifyState    string
+	NotifyTop      int
+	NotifyReport   string
 }
 
 func run(config *Config) error {
+	startTime := time.Now()
 	if config.Verbose {
 		fmt.Println("Starting linkchex...")
-		fmt.Printf("Configuration: %+v\n\n", config)
+		fmt.Printf("Configuration: %+v\n\n", redactedConfig(config))
 	}
 
-	// Discover or use provided sitemap
-	var sitemapURLs []string
-	var err error
+	// The validator's HTTP client is shared by discovery and validation
+	v := validator.NewValidator(config.Timeout, config.MaxRetries, config.Concurrency, config.Verbose)
 
-	if config.URL != "" {
-		if config.Verbose {
-			fmt.Printf("Discovering sitemap from base URL: %s\n", config.URL)
-		}
-		sitemapURLs, err = sitemap.Discover(config.URL)
-		if err != nil {
-			return fmt.Errorf("sitemap discovery failed: %w", err)
+	// Metrics are recorded as links are checked, for scraping during the run
+	var registry *metrics.Registry
+	if config.MetricsAddr != "" || config.MetricsFile != "" {
+		registry = metrics.NewRegistry()
+		registry.GaugeFunc("linkchex_run_duration_seconds", "Time spent on the run so far, in seconds", func() float64 {
+			return time.Since(startTime).Seconds()
+		})
+		v.SetMetrics(registry)
+	}
+	if config.MetricsAddr != "" {
+		if err := serveMetrics(config.MetricsAddr, registry); err != nil {
+			return err
 		}
-	} else {
 		if config.Verbose {
-			fmt.Printf("Using provided sitemap: %s\n", config.SitemapURL)
+			fmt.Printf("Serving metrics on http://%s/metrics\n", config.MetricsAddr)
 		}
-		sitemapURLs = []string{config.SitemapURL}
 	}
 
-	if config.Verbose {
-		fmt.Printf("Found %d sitemap(s)\n", len(sitemapURLs))
-		for i, url := range sitemapURLs {
-			fmt.Printf("  %d. %s\n", i+1, url)
+	// Set rate limiting if specified
+	if config.RateLimit > 0 {
+		if config.Verbose {
+			fmt.Printf("Rate limiting enabled: %.2f requests/second\n", config.RateLimit)
 		}
-		fmt.Println()
+		v.SetRateLimit(config.RateLimit)
 	}
 
-	// Parse sitemaps and extract URLs
-	var allURLs []string
-	for _, sitemapURL := range sitemapURLs {
+	var entries []sitemap.URL
+	var sitemapIssues []sitemap.Issue
+	var err error
+
+	if config.URLsFile != "" {
+		entries, err = readURLsFile(config.URLsFile)
+		if err != nil {
+			return err
+		}
 		if config.Verbose {
-			fmt.Printf("Parsing sitemap: %s\n", sitemapURL)
+			fmt.Printf("Read %d URLs from %s\n\n", len(entries), config.URLsFile)
 		}
-		urls, err := sitemap.Parse(sitemapURL)
+	} else {
+		entries, sitemapIssues, err = collectSitemapURLs(config, v.Client())
 		if err != nil {
-			return fmt.Errorf("failed to parse sitemap %s: %w", sitemapURL, err)
+			return err
 		}
-		allURLs = append(allURLs, urls...)
 	}
 
-	if config.Verbose {
-		fmt.Printf("\nDiscovered %d URLs from sitemap(s)\n\n", len(allURLs))
+	// Pages listed more than once (e.g. in several sitemaps) are validated once
+	allURLs := make([]string, 0, len(entries))
+	seenURLs := make(map[string]bool)
+	for _, entry := range entries {
+		if !seenURLs[entry.Loc] {
+			seenURLs[entry.Loc] = true
+			allURLs = append(allURLs, entry.Loc)
+		}
 	}
 
 	// If list-only mode, just display URLs and exit
@@ -157,19 +365,37 @@ func run(config *Config) error {
 		return nil
 	}
 
-	// Validate links on all pages
-	if config.Verbose {
-		fmt.Println("Starting link validation...")
+	// Set up notifiers before the crawl so configuration mistakes fail fast
+	notifiers, err := buildNotifiers(config)
+	if err != nil {
+		return err
+	}
+	notifyOptions := notify.Options{
+		On:        config.NotifyOn,
+		Throttle:  config.NotifyThrottle,
+		StateFile: config.NotifyState,
+		Top:       config.NotifyTop,
+		Site:      homepage(config.URL, allURLs),
+		ReportURL: config.NotifyReport,
+	}
+	if len(notifiers) > 0 {
+		if err := notifyOptions.Validate(); err != nil {
+			return fmt.Errorf("invalid notification settings: %w", err)
+		}
 	}
 
-	v := validator.NewValidator(config.Timeout, config.MaxRetries, config.Concurrency, config.Verbose)
-
-	// Set rate limiting if specified
-	if config.RateLimit > 0 {
-		if config.Verbose {
-			fmt.Printf("Rate limiting enabled: %.2f requests/second\n", config.RateLimit)
+	// The link graph measures depth from the homepage
+	var graphRoot string
+	if config.LinkGraph {
+		graphRoot = config.GraphRoot
+		if graphRoot == "" {
+			graphRoot = homepage(config.URL, allURLs)
 		}
-		v.SetRateLimit(config.RateLimit)
+	}
+
+	// Validate links on all pages
+	if config.Verbose {
+		fmt.Println("Starting link validation...")
 	}
 
 	// Set exclude patterns if specified
@@ -196,17 +422,220 @@ func run(config *Config) error {
 		v.SetSkipResources(true)
 	}
 
+	// Normalize link URLs so equivalent forms are checked once
+	v.SetCanonicalization(config.StripParams, config.FoldSlash)
+
+	// Set rel values to skip
+	if len(config.SkipRels) > 0 {
+		if config.Verbose {
+			fmt.Printf("Skipping links with rel: %s\n", strings.Join(config.SkipRels, ", "))
+		}
+		v.SetSkipRels(config.SkipRels)
+	}
+
+	// Respect robots.txt unless overridden
+	if !config.IgnoreRobots {
+		v.SetRespectRobots(true)
+		v.SetRobotsForLinks(config.RobotsLinks)
+	} else if config.Verbose {
+		fmt.Println("Ignoring robots.txt")
+	}
+
+	// Enable soft 404 detection
+	if config.Soft404 {
+		if err := v.SetSoft404Detection(config.Soft404Title, config.Soft404Body); err != nil {
+			return err
+		}
+		if config.Verbose {
+			fmt.Println("Soft 404 detection enabled")
+		}
+	}
+
+	// Enable content-type and size checks for assets
+	if config.CheckContent || config.MaxImageKB > 0 || config.MaxScriptKB > 0 || config.MaxCSSKB > 0 {
+		v.SetContentChecks(config.CheckContent, map[string]int64{
+			validator.AssetImage:      config.MaxImageKB * 1024,
+			validator.AssetScript:     config.MaxScriptKB * 1024,
+			validator.AssetStylesheet: config.MaxCSSKB * 1024,
+		})
+	}
+
+	// Enable the security audit
+	if config.SecurityAudit {
+		if config.Verbose {
+			fmt.Println("Security audit enabled (mixed content, insecure links, downgrades)")
+		}
+		v.SetSecurityAudit(true)
+	}
+
+	// Run HTML-structure and SEO checks on crawled pages
+	if config.CheckPages {
+		if config.Verbose {
+			fmt.Println("Page structure checks enabled")
+		}
+		v.SetPageChecks(true)
+	}
+
+	// Inspect TLS certificates of every https host contacted
+	if config.CheckTLS {
+		if config.Verbose {
+			fmt.Printf("Inspecting TLS certificates (expiry warning: %d days)\n", config.TLSExpiryDays)
+		}
+		v.SetTLSCheck(config.TLSExpiryDays)
+	}
+
+	// Validate image/video sitemap extension URLs
+	if config.CheckMedia {
+		if config.Verbose {
+			fmt.Println("Validating sitemap image and video URLs")
+		}
+		v.SetSitemapMedia(entries)
+	}
+
+	// Render pages in a headless browser so JavaScript-added links are found
+	var renderer *fetcher.ChromeRenderer
+	if config.Render {
+		renderer, err = fetcher.NewChromeRenderer(v.Client(), fetcher.ChromeOptions{
+			Path:    config.ChromePath,
+			MaxWait: config.RenderWait,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to start headless browser: %w", err)
+		}
+		defer renderer.Close()
+
+		if config.Verbose {
+			fmt.Println("Rendering pages in headless Chrome")
+		}
+		v.Client().SetRenderer(renderer)
+	}
+
+	// Load incremental state if requested
+	var state *validator.State
+	if config.StateFile != "" {
+		state, err = validator.LoadState(config.StateFile)
+		if err != nil {
+			return err
+		}
+
+		lastMods := make(map[string]time.Time)
+		for _, entry := range entries {
+			if lastMod, err := sitemap.ParseLastMod(entry.LastMod); err == nil {
+				lastMods[entry.Loc] = lastMod
+			}
+		}
+
+		if config.Verbose {
+			fmt.Printf("Incremental mode: %d pages and %d links in %s\n", len(state.Pages), len(state.Links), config.StateFile)
+		}
+		v.SetIncremental(state, lastMods, config.LinkTTL)
+	}
+
 	report := v.ValidateMultiplePages(allURLs, config.CheckExternal)
+	report.SitemapIssues = sitemapIssues
+
+	// A homepage missing from the pages is crawled for the graph only, so its
+	// links don't count towards the report or the exit code
+	var rootResults []validator.Result
+	if graphRoot != "" && !seenURLs[graphRoot] {
+		if config.Verbose {
+			fmt.Printf("Crawling %s for the link graph...\n", graphRoot)
+		}
+		rootResults = v.CrawlGraphPage(graphRoot, config.CheckExternal)
+	}
+
+	// The browser isn't needed past the crawl, and os.Exit below skips defers
+	if renderer != nil {
+		renderer.Close()
+	}
+
+	// Give broken links a second chance, so transient failures show up as flaky
+	if config.VerifyBroken {
+		flaky := v.VerifyBrokenLinks(report, validator.VerifyOptions{
+			Delay:   config.VerifyDelay,
+			UseGET:  config.VerifyGET,
+			Timeout: time.Duration(config.VerifyTimeout) * time.Second,
+		})
+		if config.Verbose {
+			fmt.Printf("Re-verification: %d broken link(s) recovered and marked flaky\n", flaky)
+		}
+	}
+
+	// Audit the sitemap entries against the crawl
+	if config.AuditSitemap {
+		if config.Verbose {
+			fmt.Println("Auditing sitemap entries...")
+		}
+		v.AuditSitemap(entries, report)
+	}
+
+	// Validate hreflang alternates
+	if config.CheckHreflang {
+		if config.Verbose {
+			fmt.Println("Checking hreflang alternates...")
+		}
+		v.CheckHreflang(entries, report)
+	}
+
+	// Saved after every pass that validates links, hreflang alternates included
+	if state != nil {
+		if err := state.Save(config.StateFile); err != nil {
+			return fmt.Errorf("failed to save state file: %w", err)
+		}
+	}
+
+	// Analyze the internal link graph
+	if config.LinkGraph && graphRoot != "" {
+		if config.Verbose {
+			fmt.Printf("Analyzing link graph from %s...\n", graphRoot)
+		}
+		graph := v.AnalyzeLinkGraph(report, allURLs, graphRoot, rootResults)
+
+		if config.GraphDOT != "" {
+			if err := validator.WriteGraphFile(graph, "dot", config.GraphDOT); err != nil {
+				return fmt.Errorf("failed to write DOT graph: %w", err)
+			}
+		}
+		if config.GraphML != "" {
+			if err := validator.WriteGraphFile(graph, "graphml", config.GraphML); err != nil {
+				return fmt.Errorf("failed to write GraphML graph: %w", err)
+			}
+		}
+	}
+
+	// Record the run for history and trend queries
+	if config.HistoryDB != "" {
+		store, err := history.Open(config.HistoryDB)
+		if err != nil {
+			return err
+		}
+		runID, err := store.RecordRun(report, config)
+		store.Close()
+		if err != nil {
+			return err
+		}
+		if config.Verbose {
+			fmt.Printf("Recorded run %d in %s\n", runID, config.HistoryDB)
+		}
+	}
 
 	// Format and output report
-	reportText, err := validator.FormatReport(report, config.Format)
-	if err != nil {
-		return fmt.Errorf("failed to format report: %w", err)
+	var reportText string
+	if config.Template != "" {
+		reportText, err = validator.RenderTemplate(report, config.Template)
+		if err != nil {
+			return err
+		}
+	} else {
+		reportText, err = validator.FormatReport(report, config.Format, validator.FormatOptions{MarkdownLimit: config.MarkdownLimit})
+		if err != nil {
+			return fmt.Errorf("failed to format report: %w", err)
+		}
 	}
 
 	if config.Output != "" {
 		// Write to file
-		if err := validator.WriteReportToFile(report, config.Format, config.Output); err != nil {
+		if err := os.WriteFile(config.Output, []byte(reportText), 0644); err != nil {
 			return fmt.Errorf("failed to write report to file: %w", err)
 		}
 		if config.Verbose {
@@ -217,6 +646,13 @@ func run(config *Config) error {
 		fmt.Println(reportText)
 	}
 
+	// Append to the GitHub Actions job summary
+	if config.GitHubSummary {
+		if err := appendGitHubSummary(validator.FormatMarkdown(report, config.MarkdownLimit)); err != nil {
+			return err
+		}
+	}
+
 	// Generate HTML report if requested
 	if config.HTMLOutput != "" {
 		if err := validator.WriteHTMLReport(report, config.HTMLOutput); err != nil {
@@ -227,6 +663,32 @@ func run(config *Config) error {
 		}
 	}
 
+	// Write the node_exporter textfile
+	if config.MetricsFile != "" {
+		registry.Gauge("linkchex_last_run_timestamp_seconds", "Unix time the last run finished").Set(float64(time.Now().Unix()))
+		if err := registry.WriteFile(config.MetricsFile); err != nil {
+			return fmt.Errorf("failed to write metrics file: %w", err)
+		}
+		if config.Verbose {
+			fmt.Printf("Metrics written to: %s\n", config.MetricsFile)
+		}
+	}
+
+	// Send notifications; a failed notifier doesn't fail the run
+	if len(notifiers) > 0 {
+		result, err := notify.Notify(report, notifiers, notifyOptions)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: notification failed: %v\n", err)
+		}
+		if config.Verbose {
+			if result.Sent {
+				fmt.Printf("Notified %d of %d destination(s)\n", len(notifiers)-result.Failed, len(notifiers))
+			} else if result.Reason != "" {
+				fmt.Printf("No notification sent: %s\n", result.Reason)
+			}
+		}
+	}
+
 	// Exit with error code if broken links found
 	if report.BrokenLinks > 0 {
 		os.Exit(1)
@@ -234,3 +696,157 @@ func run(config *Config) error {
 
 	return nil
 }
+
+// serveMetrics serves the registry at /metrics in the background
+// The listener is opened up front so a busy port fails the run immediately
+func serveMetrics(addr string, registry *metrics.Registry) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen for metrics: %w", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", registry)
+	go http.Serve(listener, mux)
+	return nil
+}
+
+// homepage returns the root URL of the site being checked, taken from the
+// --url flag or else the first page URL
+// A bare --url host is skipped since its scheme isn't known
+func homepage(baseURL string, pageURLs []string) string {
+	candidates := append([]string{baseURL}, pageURLs...)
+	for _, candidate := range candidates {
+		if parsed, err := url.Parse(candidate); err == nil && parsed.Scheme != "" && parsed.Host != "" {
+			return parsed.Scheme + "://" + parsed.Host + "/"
+		}
+	}
+	return ""
+}
+
+// appendGitHubSummary appends markdown to the file named by $GITHUB_STEP_SUMMARY
+func appendGitHubSummary(markdown string) error {
+	path := os.Getenv("GITHUB_STEP_SUMMARY")
+	if path == "" {
+		return fmt.Errorf("--github-summary requires GITHUB_STEP_SUMMARY to be set (it is set inside GitHub Actions)")
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open job summary: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.WriteString(markdown + "\n"); err != nil {
+		return fmt.Errorf("failed to write job summary: %w", err)
+	}
+	return nil
+}
+
+// splitList splits a comma-separated flag value, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// collectSitemapURLs discovers or opens the configured sitemap(s) and returns their page URLs
+// Problems with child sitemaps are returned as issues rather than errors
+func collectSitemapURLs(config *Config, client *fetcher.Client) ([]sitemap.URL, []sitemap.Issue, error) {
+	// Discover or use provided sitemap
+	var sitemapURLs []string
+
+	if config.URL != "" {
+		if config.Verbose {
+			fmt.Printf("Discovering sitemap from base URL: %s\n", config.URL)
+		}
+		discovery, err := sitemap.Discover(client, config.URL)
+		if config.Verbose && discovery != nil {
+			fmt.Println("Locations tried:")
+			for _, probe := range discovery.Probes {
+				outcome := fmt.Sprintf("%d", probe.StatusCode)
+				if probe.Error != nil {
+					outcome = probe.Error.Error()
+				}
+				fmt.Printf("  [%s] %s -> %s", probe.Source, probe.URL, outcome)
+				if len(probe.Found) > 0 {
+					fmt.Printf(" (found %d)", len(probe.Found))
+				}
+				fmt.Println()
+			}
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("sitemap discovery failed: %w", err)
+		}
+		sitemapURLs = discovery.Sitemaps
+	} else {
+		if config.Verbose {
+			fmt.Printf("Using provided sitemap: %s\n", config.SitemapURL)
+		}
+		sitemapURLs = []string{config.SitemapURL}
+	}
+
+	if config.Verbose {
+		fmt.Printf("Found %d sitemap(s)\n", len(sitemapURLs))
+		for i, url := range sitemapURLs {
+			fmt.Printf("  %d. %s\n", i+1, url)
+		}
+		fmt.Println()
+	}
+
+	// Parse sitemaps and extract URLs
+	var allURLs []sitemap.URL
+	var issues []sitemap.Issue
+	for _, sitemapURL := range sitemapURLs {
+		if config.Verbose {
+			fmt.Printf("Parsing sitemap: %s\n", sitemapURL)
+		}
+		result, err := sitemap.ParseTree(sitemapURL, sitemap.Options{MaxDepth: config.SitemapDepth, Client: client})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse sitemap %s: %w", sitemapURL, err)
+		}
+		if config.Verbose {
+			for _, issue := range result.Issues {
+				fmt.Printf("  ⚠ %s\n", issue)
+			}
+		}
+		allURLs = append(allURLs, result.URLs...)
+		issues = append(issues, result.Issues...)
+	}
+
+	if config.Verbose {
+		fmt.Printf("\nDiscovered %d URLs from sitemap(s)\n\n", len(allURLs))
+	}
+
+	return allURLs, issues, nil
+}
+
+// readURLsFile reads page URLs from a file, or from stdin when path is "-"
+func readURLsFile(path string) ([]sitemap.URL, error) {
+	var urls []string
+	var err error
+
+	if path == "-" {
+		urls, err = sitemap.ReadURLList(os.Stdin)
+	} else {
+		file, openErr := os.Open(path)
+		if openErr != nil {
+			return nil, fmt.Errorf("failed to open URLs file: %w", openErr)
+		}
+		defer file.Close()
+		urls, err = sitemap.ReadURLList(file)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]sitemap.URL, 0, len(urls))
+	for _, u := range urls {
+		entries = append(entries, sitemap.URL{Loc: u})
+	}
+	return entries, nil
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"linkchex/internal/notify"
+)
+
+// buildNotifiers creates a notifier for every configured destination
+func buildNotifiers(config *Config) ([]notify.Notifier, error) {
+	var notifiers []notify.Notifier
+
+	for _, url := range config.NotifyWebhooks {
+		webhook := notify.NewWebhook(url)
+		if config.NotifyTemplate != "" {
+			if err := webhook.LoadTemplate(config.NotifyTemplate); err != nil {
+				return nil, err
+			}
+		}
+		notifiers = append(notifiers, webhook)
+	}
+	for _, url := range config.NotifySlack {
+		notifiers = append(notifiers, notify.NewSlack(url))
+	}
+	for _, url := range config.NotifyTeams {
+		notifiers = append(notifiers, notify.NewTeams(url))
+	}
+
+	if len(config.NotifyEmail) > 0 {
+		if config.SMTPServer == "" || config.SMTPFrom == "" {
+			return nil, fmt.Errorf("--notify-email requires --smtp-server and --smtp-from")
+		}
+		notifiers = append(notifiers, &notify.Email{
+			Server:   config.SMTPServer,
+			From:     config.SMTPFrom,
+			To:       config.NotifyEmail,
+			Username: config.SMTPUser,
+			Password: os.Getenv("LINKCHEX_SMTP_PASSWORD"),
+		})
+	}
+
+	if config.NotifyTemplate != "" && len(config.NotifyWebhooks) == 0 {
+		return nil, fmt.Errorf("--notify-webhook-template requires --notify-webhook")
+	}
+	if len(notifiers) > 0 && config.NotifyState == "" && (config.NotifyOn == notify.OnNew || config.NotifyThrottle > 0) {
+		return nil, fmt.Errorf("--notify-on new and --notify-throttle require --notify-state")
+	}
+	return notifiers, nil
+}
+
+// redactedConfig returns a copy of config that is safe to print, with the
+// secrets in webhook URLs removed
+func redactedConfig(config *Config) Config {
+	redacted := *config
+	redacted.NotifyWebhooks = redactURLs(config.NotifyWebhooks)
+	redacted.NotifySlack = redactURLs(config.NotifySlack)
+	redacted.NotifyTeams = redactURLs(config.NotifyTeams)
+	return redacted
+}
+
+// redactURLs redacts each webhook URL in a list
+func redactURLs(urls []string) []string {
+	redacted := make([]string, len(urls))
+	for i, url := range urls {
+		redacted[i] = notify.RedactURL(url)
+	}
+	return redacted
+}
@@ -0,0 +1,163 @@
+package fetcher
+
+import (
+	"net/url"
+	"path"
+	"strings"
+)
+
+// DefaultTrackingParams are query parameters that only identify a campaign or
+// click and never change the page served. A trailing * matches any suffix
+var DefaultTrackingParams = []string{
+	"utm_*",
+	"gclid",
+	"dclid",
+	"fbclid",
+	"msclkid",
+	"yclid",
+	"mc_cid",
+	"mc_eid",
+	"_hsenc",
+	"_hsmi",
+}
+
+// Canonicalizer reduces equivalent URLs to a single form for caching and dedup
+// The zero value applies only the normalizations that never change which
+// resource is fetched: case, default ports, fragments and percent-encoding
+type Canonicalizer struct {
+	StripParams       []string // Query parameters to drop; a trailing * matches any suffix
+	FoldTrailingSlash bool     // Treat /a/ and /a as the same URL
+}
+
+// Canonical returns the canonical form of a URL, or the input unchanged if it
+// isn't an absolute http(s) URL
+func (c *Canonicalizer) Canonical(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return rawURL
+	}
+
+	scheme := strings.ToLower(u.Scheme)
+	host := strings.ToLower(u.Hostname())
+	if strings.Contains(host, ":") {
+		host = "[" + host + "]" // IPv6 literal
+	}
+	if port := u.Port(); port != "" && !(scheme == "http" && port == "80") && !(scheme == "https" && port == "443") {
+		host += ":" + port
+	}
+
+	p := normalizePercentEncoding(u.EscapedPath())
+	if p == "" {
+		p = "/"
+	}
+	if strings.Contains(p, "/.") {
+		// Resolve dot segments but keep a trailing slash, which path.Clean drops
+		trailing := strings.HasSuffix(p, "/")
+		p = path.Clean(p)
+		if trailing && p != "/" {
+			p += "/"
+		}
+	}
+	if c != nil && c.FoldTrailingSlash && len(p) > 1 {
+		p = strings.TrimRight(p, "/")
+		if p == "" {
+			p = "/"
+		}
+	}
+
+	canonical := scheme + "://" + host + p
+	if query := c.filterQuery(u.RawQuery); query != "" {
+		canonical += "?" + query
+	}
+	return canonical
+}
+
+// filterQuery drops tracking parameters while keeping the order of the rest
+func (c *Canonicalizer) filterQuery(rawQuery string) string {
+	if rawQuery == "" {
+		return ""
+	}
+
+	var kept []string
+	for _, pair := range strings.Split(rawQuery, "&") {
+		if pair == "" {
+			continue
+		}
+		name := pair
+		if idx := strings.Index(pair, "="); idx >= 0 {
+			name = pair[:idx]
+		}
+		if unescaped, err := url.QueryUnescape(name); err == nil {
+			name = unescaped
+		}
+		if c != nil && matchesParam(c.StripParams, name) {
+			continue
+		}
+		kept = append(kept, normalizePercentEncoding(pair))
+	}
+	return strings.Join(kept, "&")
+}
+
+// matchesParam reports whether a parameter name matches any pattern
+func matchesParam(patterns []string, name string) bool {
+	name = strings.ToLower(name)
+	for _, pattern := range patterns {
+		pattern = strings.ToLower(pattern)
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			if strings.HasPrefix(name, prefix) {
+				return true
+			}
+		} else if name == pattern {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizePercentEncoding decodes escaped unreserved characters and
+// uppercases the hex digits of the escapes that remain (RFC 3986 6.2.2)
+func normalizePercentEncoding(s string) string {
+	if !strings.Contains(s, "%") {
+		return s
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '%' && i+2 < len(s) && isHex(s[i+1]) && isHex(s[i+2]) {
+			b := unhex(s[i+1])<<4 | unhex(s[i+2])
+			if isUnreserved(b) {
+				sb.WriteByte(b)
+			} else {
+				sb.WriteByte('%')
+				sb.WriteString(strings.ToUpper(s[i+1 : i+3]))
+			}
+			i += 2
+			continue
+		}
+		sb.WriteByte(s[i])
+	}
+	return sb.String()
+}
+
+// isUnreserved reports whether a byte is an RFC 3986 unreserved character
+func isUnreserved(b byte) bool {
+	return ('a' <= b && b <= 'z') || ('A' <= b && b <= 'Z') || ('0' <= b && b <= '9') ||
+		b == '-' || b == '.' || b == '_' || b == '~'
+}
+
+// isHex reports whether a byte is a hexadecimal digit
+func isHex(b byte) bool {
+	return ('0' <= b && b <= '9') || ('a' <= b && b <= 'f') || ('A' <= b && b <= 'F')
+}
+
+// unhex returns the value of a hexadecimal digit
+func unhex(b byte) byte {
+	switch {
+	case '0' <= b && b <= '9':
+		return b - '0'
+	case 'a' <= b && b <= 'f':
+		return b - 'a' + 10
+	default:
+		return b - 'A' + 10
+	}
+}
@@ -0,0 +1,544 @@
+package fetcher
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/websocket"
+)
+
+// ChromeOptions configures the headless Chrome renderer
+type ChromeOptions struct {
+	Path    string        // Browser binary; searched on PATH when empty
+	MaxWait time.Duration // Longest to wait after load for the DOM to settle
+	Timeout time.Duration // Per-page limit for navigation and load
+}
+
+// chromeBinaries are the executable names tried when no path is given
+var chromeBinaries = []string{
+	"google-chrome",
+	"google-chrome-stable",
+	"chromium",
+	"chromium-browser",
+	"chrome",
+	"headless_shell",
+	"/Applications/Google Chrome.app/Contents/MacOS/Google Chrome",
+}
+
+// devToolsOrigin is the Origin the DevTools websocket is dialed with; it's
+// the only origin the browser accepts, so rendered pages can't drive it
+const devToolsOrigin = "http://127.0.0.1"
+
+// devToolsPattern matches the line Chrome prints once remote debugging is ready
+var devToolsPattern = regexp.MustCompile(`DevTools listening on (ws://\S+)`)
+
+// ChromeRenderer renders pages in a headless Chrome over the DevTools protocol,
+// so links added by JavaScript are visible to link extraction
+type ChromeRenderer struct {
+	client   *Client
+	opts     ChromeOptions
+	cmd      *exec.Cmd
+	dataDir  string
+	endpoint string // http://host:port of the DevTools HTTP interface
+	closed   sync.Once
+}
+
+// NewChromeRenderer launches a headless browser and connects to its DevTools endpoint
+func NewChromeRenderer(client *Client, opts ChromeOptions) (*ChromeRenderer, error) {
+	if opts.MaxWait <= 0 {
+		opts.MaxWait = 5 * time.Second
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = client.httpClient.Timeout
+	}
+
+	path := opts.Path
+	if path == "" {
+		for _, name := range chromeBinaries {
+			if found, err := exec.LookPath(name); err == nil {
+				path = found
+				break
+			}
+		}
+		if path == "" {
+			return nil, fmt.Errorf("no Chrome or Chromium binary found (tried %s)", strings.Join(chromeBinaries, ", "))
+		}
+	}
+
+	dataDir, err := os.MkdirTemp("", "linkchex-chrome-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create browser profile: %w", err)
+	}
+
+	args := []string{
+		"--headless=new",
+		"--disable-gpu",
+		"--no-first-run",
+		"--no-default-browser-check",
+		"--disable-extensions",
+		"--mute-audio",
+		"--remote-debugging-port=0",
+		"--remote-allow-origins=" + devToolsOrigin,
+		"--user-data-dir=" + dataDir,
+		"--user-agent=" + client.userAgent,
+	}
+	if os.Geteuid() == 0 {
+		// Chrome refuses to start its sandbox as root, which is common in containers
+		args = append(args, "--no-sandbox")
+	}
+	cmd := exec.Command(path, append(args, "about:blank")...)
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		os.RemoveAll(dataDir)
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		os.RemoveAll(dataDir)
+		return nil, fmt.Errorf("failed to start %s: %w", path, err)
+	}
+
+	r := &ChromeRenderer{
+		client:  client,
+		opts:    opts,
+		cmd:     cmd,
+		dataDir: dataDir,
+	}
+
+	wsURL, err := waitForDevTools(stderr, 20*time.Second)
+	if err != nil {
+		r.Close()
+		return nil, err
+	}
+	parsed, err := url.Parse(wsURL)
+	if err != nil {
+		r.Close()
+		return nil, fmt.Errorf("invalid DevTools URL %q: %w", wsURL, err)
+	}
+	r.endpoint = "http://" + parsed.Host
+
+	return r, nil
+}
+
+// waitForDevTools reads browser stderr until the DevTools URL is announced
+// The rest of stderr is drained so the browser never blocks on a full pipe
+func waitForDevTools(stderr io.Reader, timeout time.Duration) (string, error) {
+	found := make(chan string, 1)
+	go func() {
+		scanner := bufio.NewScanner(stderr)
+		announced := false
+		for scanner.Scan() {
+			if match := devToolsPattern.FindStringSubmatch(scanner.Text()); match != nil && !announced {
+				announced = true
+				found <- match[1]
+			}
+		}
+		if !announced {
+			close(found)
+		}
+	}()
+
+	select {
+	case wsURL, ok := <-found:
+		if !ok {
+			return "", errors.New("browser exited before DevTools was ready")
+		}
+		return wsURL, nil
+	case <-time.After(timeout):
+		return "", errors.New("timed out waiting for browser DevTools endpoint")
+	}
+}
+
+// Close shuts the browser down and removes its temporary profile
+// It is safe to call more than once
+func (r *ChromeRenderer) Close() error {
+	var err error
+	r.closed.Do(func() {
+		if r.cmd != nil && r.cmd.Process != nil {
+			r.cmd.Process.Kill()
+			r.cmd.Wait()
+		}
+		err = os.RemoveAll(r.dataDir)
+	})
+	return err
+}
+
+// Render opens the page in a new tab, waits for load and for the DOM to stop
+// changing, then returns the serialized document as the response body
+// Failed renders are retried like plain GETs
+func (r *ChromeRenderer) Render(pageURL string) *Response {
+	var lastErr error
+	startTime := time.Now()
+
+	for attempt := 0; attempt <= r.client.maxRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(r.client.retryDelay * time.Duration(attempt))
+		}
+		r.client.waitForHost(pageURL)
+
+		resp, err := r.render(pageURL)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		resp.Duration = time.Since(startTime)
+		resp.TLS = r.client.inspectTLS(resp.FinalURL)
+		return resp
+	}
+
+	return &Response{
+		StatusCode:    0,
+		Status:        "Failed",
+		URL:           pageURL,
+		FinalURL:      pageURL,
+		ContentLength: -1,
+		Error:         fmt.Errorf("render failed after %d attempts: %w", r.client.maxRetries+1, lastErr),
+		Duration:      time.Since(startTime),
+		TLS:           r.client.inspectTLS(pageURL),
+	}
+}
+
+// devToolsTarget is a browser tab as listed by the DevTools HTTP interface
+type devToolsTarget struct {
+	ID                   string `json:"id"`
+	WebSocketDebuggerURL string `json:"webSocketDebuggerUrl"`
+}
+
+// render drives a single tab through navigation and DOM capture
+func (r *ChromeRenderer) render(pageURL string) (*Response, error) {
+	target, err := r.newTarget()
+	if err != nil {
+		return nil, err
+	}
+	defer r.closeTarget(target.ID)
+
+	conn, err := dialCDP(target.WebSocketDebuggerURL)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.close()
+
+	deadline := time.Now().Add(r.opts.Timeout)
+
+	for _, method := range []string{"Page.enable", "Network.enable"} {
+		if _, err := conn.call(method, nil, deadline); err != nil {
+			return nil, err
+		}
+	}
+
+	var navigated struct {
+		FrameID   string `json:"frameId"`
+		ErrorText string `json:"errorText"`
+	}
+	result, err := conn.call("Page.navigate", map[string]string{"url": pageURL}, deadline)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(result, &navigated); err != nil {
+		return nil, err
+	}
+	if navigated.ErrorText != "" {
+		return nil, errors.New(navigated.ErrorText)
+	}
+
+	// The main document's response gives the status code and headers
+	resp := &Response{URL: pageURL, Header: make(http.Header)}
+	gotDocument := false
+	for loaded := false; !loaded; {
+		event, err := conn.nextEvent(deadline)
+		if err != nil {
+			return nil, fmt.Errorf("waiting for page load: %w", err)
+		}
+
+		switch event.Method {
+		case "Network.responseReceived":
+			var params struct {
+				Type     string `json:"type"`
+				FrameID  string `json:"frameId"`
+				Response struct {
+					URL        string            `json:"url"`
+					Status     int               `json:"status"`
+					StatusText string            `json:"statusText"`
+					Headers    map[string]string `json:"headers"`
+					MimeType   string            `json:"mimeType"`
+				} `json:"response"`
+			}
+			if json.Unmarshal(event.Params, &params) != nil || params.Type != "Document" || params.FrameID != navigated.FrameID || gotDocument {
+				continue
+			}
+			gotDocument = true
+			resp.StatusCode = params.Response.Status
+			statusText := params.Response.StatusText
+			if statusText == "" {
+				statusText = http.StatusText(params.Response.Status) // HTTP/2 has no reason phrase
+			}
+			resp.Status = fmt.Sprintf("%d %s", params.Response.Status, statusText)
+			for name, value := range params.Response.Headers {
+				resp.Header.Set(name, value)
+			}
+			resp.ContentType = mediaType(params.Response.MimeType)
+		case "Page.loadEventFired":
+			loaded = true
+		}
+	}
+
+	// Scripts often add links after load; wait until the DOM stops growing
+	if err := r.waitForSettledDOM(conn); err != nil {
+		return nil, err
+	}
+
+	var document struct {
+		HTML string `json:"html"`
+		URL  string `json:"url"`
+	}
+	if err := conn.evaluate(`({html: document.documentElement.outerHTML, url: location.href})`, &document, deadline.Add(r.opts.MaxWait)); err != nil {
+		return nil, err
+	}
+
+	if !gotDocument {
+		// Pages served from cache or a service worker may not report a response
+		resp.StatusCode = http.StatusOK
+		resp.Status = "200 OK"
+		resp.ContentType = "text/html"
+	}
+	resp.FinalURL = document.URL
+	if document.URL != pageURL {
+		resp.Redirects = []string{pageURL, document.URL}
+	}
+	resp.Body = []byte(document.HTML)
+	resp.ContentLength = int64(len(resp.Body))
+
+	return resp, nil
+}
+
+// waitForSettledDOM polls the element count until it is unchanged for two
+// consecutive checks, or until MaxWait elapses
+func (r *ChromeRenderer) waitForSettledDOM(conn *cdpConn) error {
+	const interval = 250 * time.Millisecond
+
+	deadline := time.Now().Add(r.opts.MaxWait)
+	last, stable := -1, 0
+	for time.Now().Before(deadline) {
+		var count int
+		if err := conn.evaluate(`document.getElementsByTagName("*").length`, &count, deadline.Add(interval)); err != nil {
+			return err
+		}
+		if count == last {
+			stable++
+			if stable >= 2 {
+				return nil
+			}
+		} else {
+			last, stable = count, 0
+		}
+		time.Sleep(interval)
+	}
+	return nil
+}
+
+// newTarget opens a blank tab through the DevTools HTTP interface
+func (r *ChromeRenderer) newTarget() (*devToolsTarget, error) {
+	req, err := http.NewRequest(http.MethodPut, r.endpoint+"/json/new?about:blank", nil)
+	if err != nil {
+		return nil, err
+	}
+	httpResp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open browser tab: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	var target devToolsTarget
+	if err := json.NewDecoder(httpResp.Body).Decode(&target); err != nil {
+		return nil, fmt.Errorf("failed to open browser tab: %w", err)
+	}
+	if target.WebSocketDebuggerURL == "" {
+		return nil, errors.New("failed to open browser tab: no debugger URL")
+	}
+	return &target, nil
+}
+
+// closeTarget closes a tab opened by newTarget
+func (r *ChromeRenderer) closeTarget(id string) {
+	if httpResp, err := http.Get(r.endpoint + "/json/close/" + id); err == nil {
+		httpResp.Body.Close()
+	}
+}
+
+// cdpMessage is a DevTools protocol command reply or event
+type cdpMessage struct {
+	ID     int             `json:"id,omitempty"`
+	Method string          `json:"method,omitempty"`
+	Params json.RawMessage `json:"params,omitempty"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// cdpConn is a DevTools protocol session with a single tab
+type cdpConn struct {
+	ws      *websocket.Conn
+	nextID  int
+	pending map[int]chan cdpMessage
+	events  []cdpMessage  // Queued until nextEvent takes them
+	arrived chan struct{} // Signalled when an event is queued
+	done    chan struct{}
+	err     error // Why the read loop stopped
+	mu      sync.Mutex
+}
+
+// dialCDP connects to a tab's debugger websocket and starts reading messages
+func dialCDP(wsURL string) (*cdpConn, error) {
+	ws, err := websocket.Dial(wsURL, "", devToolsOrigin)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to DevTools: %w", err)
+	}
+	ws.MaxPayloadBytes = 64 << 20 // Serialized documents can be large
+
+	conn := &cdpConn{
+		ws:      ws,
+		pending: make(map[int]chan cdpMessage),
+		arrived: make(chan struct{}, 1),
+		done:    make(chan struct{}),
+	}
+	go conn.readLoop()
+	return conn, nil
+}
+
+// readLoop routes replies to their callers and queues events
+func (c *cdpConn) readLoop() {
+	defer close(c.done)
+	for {
+		var msg cdpMessage
+		if err := websocket.JSON.Receive(c.ws, &msg); err != nil {
+			c.mu.Lock()
+			c.err = err
+			c.mu.Unlock()
+			return
+		}
+
+		if msg.ID == 0 {
+			c.mu.Lock()
+			c.events = append(c.events, msg)
+			c.mu.Unlock()
+			select {
+			case c.arrived <- struct{}{}:
+			default:
+			}
+			continue
+		}
+
+		c.mu.Lock()
+		reply, ok := c.pending[msg.ID]
+		delete(c.pending, msg.ID)
+		c.mu.Unlock()
+		if ok {
+			reply <- msg
+		}
+	}
+}
+
+// call sends a command and waits for its result
+func (c *cdpConn) call(method string, params interface{}, deadline time.Time) (json.RawMessage, error) {
+	c.mu.Lock()
+	c.nextID++
+	id := c.nextID
+	reply := make(chan cdpMessage, 1)
+	c.pending[id] = reply
+	c.mu.Unlock()
+
+	command := map[string]interface{}{"id": id, "method": method}
+	if params != nil {
+		command["params"] = params
+	}
+	if err := websocket.JSON.Send(c.ws, command); err != nil {
+		return nil, fmt.Errorf("%s: %w", method, err)
+	}
+
+	select {
+	case msg := <-reply:
+		if msg.Error != nil {
+			return nil, fmt.Errorf("%s: %s", method, msg.Error.Message)
+		}
+		return msg.Result, nil
+	case <-c.done:
+		return nil, fmt.Errorf("%s: connection closed: %v", method, c.readErr())
+	case <-time.After(time.Until(deadline)):
+		return nil, fmt.Errorf("%s: timed out", method)
+	}
+}
+
+// evaluate runs a JavaScript expression in the page and decodes its value
+func (c *cdpConn) evaluate(expression string, value interface{}, deadline time.Time) error {
+	result, err := c.call("Runtime.evaluate", map[string]interface{}{
+		"expression":    expression,
+		"returnByValue": true,
+	}, deadline)
+	if err != nil {
+		return err
+	}
+
+	var evaluated struct {
+		Result struct {
+			Value json.RawMessage `json:"value"`
+		} `json:"result"`
+		ExceptionDetails *struct {
+			Text string `json:"text"`
+		} `json:"exceptionDetails"`
+	}
+	if err := json.Unmarshal(result, &evaluated); err != nil {
+		return err
+	}
+	if evaluated.ExceptionDetails != nil {
+		return fmt.Errorf("Runtime.evaluate: %s", evaluated.ExceptionDetails.Text)
+	}
+	return json.Unmarshal(evaluated.Result.Value, value)
+}
+
+// nextEvent waits for the next protocol event
+func (c *cdpConn) nextEvent(deadline time.Time) (cdpMessage, error) {
+	timeout := time.After(time.Until(deadline))
+	for {
+		c.mu.Lock()
+		if len(c.events) > 0 {
+			msg := c.events[0]
+			c.events = c.events[1:]
+			c.mu.Unlock()
+			return msg, nil
+		}
+		c.mu.Unlock()
+
+		select {
+		case <-c.arrived:
+		case <-c.done:
+			return cdpMessage{}, fmt.Errorf("connection closed: %v", c.readErr())
+		case <-timeout:
+			return cdpMessage{}, errors.New("timed out")
+		}
+	}
+}
+
+// readErr returns why the read loop stopped
+func (c *cdpConn) readErr() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+// close ends the session
+func (c *cdpConn) close() {
+	c.ws.Close()
+}
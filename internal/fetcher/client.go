@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
 )
 
@@ -14,6 +16,10 @@ type Client struct {
 	retryDelay  time.Duration
 	userAgent   string
 	rateLimiter *RateLimiter
+	hostLimiter *HostRateLimiter
+	robots      *robotsCache
+	tls         *tlsCache
+	renderer    Renderer
 }
 
 // NewClient creates a new HTTP client with the specified configuration
@@ -26,7 +32,7 @@ func NewClient(timeout int, maxRetries int) *Client {
 		DisableKeepAlives:   false,
 	}
 
-	return &Client{
+	c := &Client{
 		httpClient: &http.Client{
 			Timeout:   time.Duration(timeout) * time.Second,
 			Transport: transport,
@@ -42,7 +48,13 @@ func NewClient(timeout int, maxRetries int) *Client {
 		retryDelay:  1 * time.Second,
 		userAgent:   "Linkchex/0.1.0 (Link Validator)",
 		rateLimiter: NewRateLimiter(0), // No rate limiting by default
+		hostLimiter: NewHostRateLimiter(),
+		robots:      newRobotsCache(),
+		tls:         newTLSCache(),
 	}
+	c.renderer = &httpRenderer{client: c}
+
+	return c
 }
 
 // SetRateLimit sets the rate limit for requests (requests per second)
@@ -53,15 +65,71 @@ func (c *Client) SetRateLimit(requestsPerSecond float64) {
 	c.rateLimiter = NewRateLimiter(requestsPerSecond)
 }
 
+// WithTimeout returns a client sharing this one's connections, rate limits
+// and caches but with a different request timeout (0 keeps the current one)
+func (c *Client) WithTimeout(timeout time.Duration) *Client {
+	if timeout <= 0 {
+		return c
+	}
+	clone := *c
+	httpClient := *c.httpClient
+	httpClient.Timeout = timeout
+	clone.httpClient = &httpClient
+	if _, ok := c.renderer.(*httpRenderer); ok {
+		clone.renderer = &httpRenderer{client: &clone}
+	}
+	return &clone
+}
+
+// waitForHost applies the global and per-host rate limits for a URL
+func (c *Client) waitForHost(rawURL string) {
+	if c.rateLimiter != nil {
+		c.rateLimiter.Wait()
+	}
+	if parsed, err := url.Parse(rawURL); err == nil {
+		c.hostLimiter.Wait(parsed.Host)
+	}
+}
+
+// redirectChain reconstructs the URLs visited while following redirects
+func redirectChain(resp *http.Response) []string {
+	if resp.Request == nil || resp.Request.Response == nil {
+		return nil
+	}
+
+	var chain []string
+	for req := resp.Request; req != nil; {
+		chain = append([]string{req.URL.String()}, chain...)
+		if req.Response == nil {
+			break
+		}
+		req = req.Response.Request
+	}
+	return chain
+}
+
+// mediaType strips parameters (e.g. charset) from a Content-Type header
+func mediaType(contentType string) string {
+	if idx := strings.Index(contentType, ";"); idx >= 0 {
+		contentType = contentType[:idx]
+	}
+	return strings.ToLower(strings.TrimSpace(contentType))
+}
+
 // Response contains the result of an HTTP request
 type Response struct {
-	StatusCode int
-	Status     string
-	URL        string
-	FinalURL   string // After redirects
-	Body       []byte
-	Error      error
-	Duration   time.Duration
+	StatusCode    int
+	Status        string
+	URL           string
+	FinalURL      string   // After redirects
+	Redirects     []string // Every URL visited, from the original to FinalURL (nil if none)
+	Header        http.Header
+	ContentType   string // Media type without parameters, lowercased
+	ContentLength int64  // Body size in bytes, -1 if unknown
+	Body          []byte
+	Error         error
+	Duration      time.Duration
+	TLS           *TLSInfo // Certificate details, when TLS inspection is enabled
 }
 
 // Get performs an HTTP GET request with retry logic
@@ -76,9 +144,7 @@ func (c *Client) Get(url string) *Response {
 		}
 
 		// Apply rate limiting
-		if c.rateLimiter != nil {
-			c.rateLimiter.Wait()
-		}
+		c.waitForHost(url)
 
 		req, err := http.NewRequest("GET", url, nil)
 		if err != nil {
@@ -105,26 +171,33 @@ func (c *Client) Get(url string) *Response {
 		duration := time.Since(startTime)
 
 		return &Response{
-			StatusCode: resp.StatusCode,
-			Status:     resp.Status,
-			URL:        url,
-			FinalURL:   resp.Request.URL.String(),
-			Body:       body,
-			Error:      nil,
-			Duration:   duration,
+			StatusCode:    resp.StatusCode,
+			Status:        resp.Status,
+			URL:           url,
+			FinalURL:      resp.Request.URL.String(),
+			Redirects:     redirectChain(resp),
+			Header:        resp.Header,
+			ContentType:   mediaType(resp.Header.Get("Content-Type")),
+			ContentLength: int64(len(body)),
+			Body:          body,
+			Error:         nil,
+			Duration:      duration,
+			TLS:           c.recordTLS(resp),
 		}
 	}
 
 	// All retries failed
 	duration := time.Since(startTime)
 	return &Response{
-		StatusCode: 0,
-		Status:     "Failed",
-		URL:        url,
-		FinalURL:   url,
-		Body:       nil,
-		Error:      fmt.Errorf("failed after %d attempts: %w", c.maxRetries+1, lastErr),
-		Duration:   duration,
+		StatusCode:    0,
+		Status:        "Failed",
+		URL:           url,
+		FinalURL:      url,
+		ContentLength: -1,
+		Body:          nil,
+		Error:         fmt.Errorf("failed after %d attempts: %w", c.maxRetries+1, lastErr),
+		Duration:      duration,
+		TLS:           c.inspectTLSFailure(url, lastErr),
 	}
 }
 
@@ -139,9 +212,7 @@ func (c *Client) Head(url string) *Response {
 		}
 
 		// Apply rate limiting
-		if c.rateLimiter != nil {
-			c.rateLimiter.Wait()
-		}
+		c.waitForHost(url)
 
 		req, err := http.NewRequest("HEAD", url, nil)
 		if err != nil {
@@ -164,24 +235,31 @@ func (c *Client) Head(url string) *Response {
 		duration := time.Since(startTime)
 
 		return &Response{
-			StatusCode: resp.StatusCode,
-			Status:     resp.Status,
-			URL:        url,
-			FinalURL:   resp.Request.URL.String(),
-			Body:       nil,
-			Error:      nil,
-			Duration:   duration,
+			StatusCode:    resp.StatusCode,
+			Status:        resp.Status,
+			URL:           url,
+			FinalURL:      resp.Request.URL.String(),
+			Redirects:     redirectChain(resp),
+			Header:        resp.Header,
+			ContentType:   mediaType(resp.Header.Get("Content-Type")),
+			ContentLength: resp.ContentLength,
+			Body:          nil,
+			Error:         nil,
+			Duration:      duration,
+			TLS:           c.recordTLS(resp),
 		}
 	}
 
 	duration := time.Since(startTime)
 	return &Response{
-		StatusCode: 0,
-		Status:     "Failed",
-		URL:        url,
-		FinalURL:   url,
-		Body:       nil,
-		Error:      fmt.Errorf("failed after %d attempts: %w", c.maxRetries+1, lastErr),
-		Duration:   duration,
+		StatusCode:    0,
+		Status:        "Failed",
+		URL:           url,
+		FinalURL:      url,
+		ContentLength: -1,
+		Body:          nil,
+		Error:         fmt.Errorf("failed after %d attempts: %w", c.maxRetries+1, lastErr),
+		Duration:      duration,
+		TLS:           c.inspectTLSFailure(url, lastErr),
 	}
 }
@@ -9,25 +9,76 @@ import (
 
 // Link represents an extracted link from HTML
 type Link struct {
-	URL      string
-	Tag      string // a, img, link, script
-	Attr     string // href, src
-	Text     string // Link text for <a> tags
+	URL        string
+	Tag        string   // a, img, link, script
+	Attr       string   // href, src
+	Text       string   // Link text for <a> tags
+	Rel        []string // Lowercased rel values (nofollow, stylesheet, ...)
+	Download   bool     // Whether the element has a download attribute
 	IsExternal bool
 }
 
+// HasRel reports whether the link carries the given rel value
+func (l Link) HasRel(rel string) bool {
+	return containsRel(l.Rel, strings.ToLower(rel))
+}
+
+// nonFetchableRels are <link> relations that point at origins rather than resources
+var nonFetchableRels = []string{"preconnect", "dns-prefetch"}
+
+// Page is everything extracted from one parse of a page's HTML
+type Page struct {
+	Links     []Link
+	Meta      PageMeta
+	Structure PageStructure
+}
+
+// ParsePage parses HTML content once and extracts its links, metadata and
+// on-page structure
+func ParsePage(htmlContent []byte, baseURL string, skipResources bool) (Page, error) {
+	var page Page
+
+	doc, err := html.Parse(strings.NewReader(string(htmlContent)))
+	if err != nil {
+		return page, err
+	}
+
+	if page.Links, err = extractLinks(doc, baseURL, skipResources); err != nil {
+		return page, err
+	}
+	if page.Meta, err = extractPageMeta(doc, baseURL); err != nil {
+		return page, err
+	}
+	page.Structure = extractPageStructure(doc)
+	return page, nil
+}
+
 // ExtractLinks extracts all links from HTML content
+// Relative URLs are resolved against the document's <base href> when present
 func ExtractLinks(htmlContent []byte, baseURL string, skipResources bool) ([]Link, error) {
 	doc, err := html.Parse(strings.NewReader(string(htmlContent)))
 	if err != nil {
 		return nil, err
 	}
+	return extractLinks(doc, baseURL, skipResources)
+}
 
-	base, err := url.Parse(baseURL)
+// extractLinks extracts all links from a parsed document
+func extractLinks(doc *html.Node, baseURL string, skipResources bool) ([]Link, error) {
+	pageURL, err := url.Parse(baseURL)
 	if err != nil {
 		return nil, err
 	}
 
+	// Relative links resolve against <base href>, but external-ness is
+	// still judged against the page itself
+	base := pageURL
+	if href := findBaseHref(doc); href != "" {
+		if parsed, err := url.Parse(href); err == nil {
+			base = pageURL.ResolveReference(parsed)
+		}
+	}
+
 	var links []Link
 	var extract func(*html.Node)
 
@@ -39,10 +90,12 @@ func ExtractLinks(htmlContent []byte, baseURL string, skipResources bool) ([]Lin
 			case "a":
 				if href := getAttr(n, "href"); href != "" {
 					link = Link{
-						URL:  href,
-						Tag:  "a",
-						Attr: "href",
-						Text: extractText(n),
+						URL:      href,
+						Tag:      "a",
+						Attr:     "href",
+						Text:     extractText(n),
+						Rel:      parseRel(getAttr(n, "rel")),
+						Download: hasAttr(n, "download"),
 					}
 				}
 			case "img":
@@ -57,11 +110,16 @@ func ExtractLinks(htmlContent []byte, baseURL string, skipResources bool) ([]Lin
 				if skipResources {
 					break // Skip <link> tags if skipResources is true
 				}
+				rel := parseRel(getAttr(n, "rel"))
+				if isNonFetchable(rel) {
+					break // preconnect/dns-prefetch name an origin, not a resource
+				}
 				if href := getAttr(n, "href"); href != "" {
 					link = Link{
 						URL:  href,
 						Tag:  "link",
 						Attr: "href",
+						Rel:  rel,
 					}
 				}
 			case "script":
@@ -83,7 +141,7 @@ func ExtractLinks(htmlContent []byte, baseURL string, skipResources bool) ([]Lin
 				if err == nil {
 					absoluteURL := base.ResolveReference(parsedURL)
 					link.URL = absoluteURL.String()
-					link.IsExternal = isExternalLink(base, absoluteURL)
+					link.IsExternal = isExternalLink(pageURL, absoluteURL)
 				}
 				links = append(links, link)
 			}
@@ -98,6 +156,182 @@ func ExtractLinks(htmlContent []byte, baseURL string, skipResources bool) ([]Lin
 	return links, nil
 }
 
+// PageMeta holds document-level metadata extracted from a page's <head>
+type PageMeta struct {
+	Canonical  string      // Absolute URL from <link rel="canonical">
+	Robots     string      // Lowercased content of <meta name="robots">
+	Alternates []Alternate // <link rel="alternate" hreflang="..."> entries
+}
+
+// Alternate is a language alternate of a page declared with hreflang
+type Alternate struct {
+	Hreflang string
+	URL      string // Absolute URL
+}
+
+// NoIndex reports whether the robots meta tag forbids indexing
+func (m PageMeta) NoIndex() bool {
+	for _, directive := range strings.Split(m.Robots, ",") {
+		directive = strings.TrimSpace(directive)
+		if directive == "noindex" || directive == "none" {
+			return true
+		}
+	}
+	return false
+}
+
+// ExtractPageMeta extracts canonical, robots and hreflang metadata from HTML content
+func ExtractPageMeta(htmlContent []byte, baseURL string) (PageMeta, error) {
+	doc, err := html.Parse(strings.NewReader(string(htmlContent)))
+	if err != nil {
+		return PageMeta{}, err
+	}
+	return extractPageMeta(doc, baseURL)
+}
+
+// extractPageMeta extracts canonical, robots and hreflang metadata from a parsed document
+func extractPageMeta(doc *html.Node, baseURL string) (PageMeta, error) {
+	var meta PageMeta
+
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return meta, err
+	}
+	if href := findBaseHref(doc); href != "" {
+		if parsed, err := url.Parse(href); err == nil {
+			base = base.ResolveReference(parsed)
+		}
+	}
+
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			switch n.Data {
+			case "link":
+				rels := parseRel(getAttr(n, "rel"))
+				if meta.Canonical == "" && containsRel(rels, "canonical") {
+					if parsed, err := url.Parse(getAttr(n, "href")); err == nil {
+						meta.Canonical = base.ResolveReference(parsed).String()
+					}
+				}
+				if hreflang := getAttr(n, "hreflang"); hreflang != "" && containsRel(rels, "alternate") {
+					if parsed, err := url.Parse(getAttr(n, "href")); err == nil {
+						meta.Alternates = append(meta.Alternates, Alternate{
+							Hreflang: hreflang,
+							URL:      base.ResolveReference(parsed).String(),
+						})
+					}
+				}
+			case "meta":
+				if strings.EqualFold(getAttr(n, "name"), "robots") {
+					meta.Robots = strings.ToLower(getAttr(n, "content"))
+				}
+			}
+		}
+
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+
+	walk(doc)
+	return meta, nil
+}
+
+// ExtractPageText returns the document title and its visible text content,
+// excluding <script>, <style> and <noscript> blocks
+func ExtractPageText(htmlContent []byte) (string, string, error) {
+	doc, err := html.Parse(strings.NewReader(string(htmlContent)))
+	if err != nil {
+		return "", "", err
+	}
+
+	var title string
+	var text strings.Builder
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			switch n.Data {
+			case "script", "style", "noscript", "template":
+				return
+			case "title":
+				if title == "" {
+					title = extractText(n)
+				}
+				return
+			}
+		}
+		if n.Type == html.TextNode {
+			if data := strings.TrimSpace(n.Data); data != "" {
+				text.WriteString(data)
+				text.WriteString(" ")
+			}
+		}
+
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+
+	walk(doc)
+	return title, strings.TrimSpace(text.String()), nil
+}
+
+// containsRel reports whether a parsed rel list contains a value
+func containsRel(rels []string, rel string) bool {
+	for _, r := range rels {
+		if r == rel {
+			return true
+		}
+	}
+	return false
+}
+
+// findBaseHref returns the href of the first <base> element with one, per the HTML spec
+func findBaseHref(n *html.Node) string {
+	if n.Type == html.ElementNode && n.Data == "base" {
+		if href := getAttr(n, "href"); href != "" {
+			return href
+		}
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if href := findBaseHref(c); href != "" {
+			return href
+		}
+	}
+	return ""
+}
+
+// parseRel splits a rel attribute into its lowercased space-separated values
+func parseRel(rel string) []string {
+	if rel == "" {
+		return nil
+	}
+	return strings.Fields(strings.ToLower(rel))
+}
+
+// isNonFetchable reports whether any rel value marks the link as a connection hint
+func isNonFetchable(rels []string) bool {
+	for _, r := range rels {
+		for _, nf := range nonFetchableRels {
+			if r == nf {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasAttr reports whether an HTML node has the given attribute, even if empty
+func hasAttr(n *html.Node, key string) bool {
+	for _, attr := range n.Attr {
+		if attr.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
 // getAttr gets an attribute value from an HTML node
 func getAttr(n *html.Node, key string) string {
 	for _, attr := range n.Attr {
@@ -127,16 +361,19 @@ func isExternalLink(base, target *url.URL) bool {
 }
 
 // FilterLinks filters links based on criteria
-func FilterLinks(links []Link, includeExternal bool) []Link {
+// Duplicates are detected on the canonical form of each URL; pass a nil
+// canonicalizer for the safe default normalizations only
+func FilterLinks(links []Link, includeExternal bool, canon *Canonicalizer) []Link {
 	var filtered []Link
 	seen := make(map[string]bool)
 
 	for _, link := range links {
 		// Skip duplicates
-		if seen[link.URL] {
+		key := canon.Canonical(link.URL)
+		if seen[key] {
 			continue
 		}
-		seen[link.URL] = true
+		seen[key] = true
 
 		// Skip external links if not included
 		if !includeExternal && link.IsExternal {
@@ -156,3 +393,27 @@ func FilterLinks(links []Link, includeExternal bool) []Link {
 
 	return filtered
 }
+
+// FilterLinksByRel drops links carrying any of the given rel values
+// The pseudo-value "download" matches links with a download attribute
+func FilterLinksByRel(links []Link, skipRels []string) []Link {
+	if len(skipRels) == 0 {
+		return links
+	}
+
+	var filtered []Link
+	for _, link := range links {
+		skip := false
+		for _, rel := range skipRels {
+			if link.HasRel(rel) || (strings.EqualFold(rel, "download") && link.Download) {
+				skip = true
+				break
+			}
+		}
+		if !skip {
+			filtered = append(filtered, link)
+		}
+	}
+
+	return filtered
+}
@@ -59,13 +59,16 @@ func (rl *RateLimiter) generate() {
 	}
 }
 
-// Wait blocks until a token is available
+// Wait blocks until a token is available or the limiter is stopped
 func (rl *RateLimiter) Wait() {
 	if rl.requestsPerSecond == 0 {
 		// No rate limiting
 		return
 	}
-	<-rl.tokens
+	select {
+	case <-rl.tokens:
+	case <-rl.stop:
+	}
 }
 
 // Stop stops the rate limiter
@@ -78,3 +81,50 @@ func (rl *RateLimiter) Stop() {
 		rl.ticker.Stop()
 	})
 }
+
+// HostRateLimiter applies independent rate limits per host
+type HostRateLimiter struct {
+	limiters map[string]*RateLimiter
+	mu       sync.RWMutex
+}
+
+// NewHostRateLimiter creates an empty per-host rate limiter
+func NewHostRateLimiter() *HostRateLimiter {
+	return &HostRateLimiter{
+		limiters: make(map[string]*RateLimiter),
+	}
+}
+
+// SetHostRate sets the rate limit for a single host if it has none yet
+// The first limit is kept since requests may already be waiting on it, e.g.
+// when http:// and https:// robots.txt both set a Crawl-delay for the host
+func (h *HostRateLimiter) SetHostRate(host string, requestsPerSecond float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.limiters[host]; ok {
+		return
+	}
+	h.limiters[host] = NewRateLimiter(requestsPerSecond)
+}
+
+// Wait blocks until a token is available for the host (no-op for unlimited hosts)
+func (h *HostRateLimiter) Wait(host string) {
+	h.mu.RLock()
+	limiter, ok := h.limiters[host]
+	h.mu.RUnlock()
+
+	if ok {
+		limiter.Wait()
+	}
+}
+
+// Stop stops all per-host rate limiters
+func (h *HostRateLimiter) Stop() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, limiter := range h.limiters {
+		limiter.Stop()
+	}
+}
@@ -0,0 +1,38 @@
+package fetcher
+
+// Renderer produces the HTML that a page's links are extracted from
+type Renderer interface {
+	// Render loads a page and returns its final HTML as the response body
+	Render(url string) *Response
+	// Close releases any resources held by the renderer
+	Close() error
+}
+
+// httpRenderer is the default renderer: a plain GET through the client
+type httpRenderer struct {
+	client *Client
+}
+
+// Render fetches the page without executing any JavaScript
+func (r *httpRenderer) Render(url string) *Response {
+	return r.client.Get(url)
+}
+
+// Close is a no-op for plain HTTP fetches
+func (r *httpRenderer) Close() error {
+	return nil
+}
+
+// SetRenderer replaces how pages are loaded for link extraction
+// Passing nil restores the default plain HTTP fetch
+func (c *Client) SetRenderer(r Renderer) {
+	if r == nil {
+		r = &httpRenderer{client: c}
+	}
+	c.renderer = r
+}
+
+// Render loads a page through the configured renderer
+func (c *Client) Render(url string) *Response {
+	return c.renderer.Render(url)
+}
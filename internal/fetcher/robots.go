@@ -0,0 +1,102 @@
+package fetcher
+
+import (
+	"bytes"
+	"net/url"
+	"sync"
+	"time"
+
+	"linkchex/internal/robots"
+)
+
+// robotsEntry caches the robots.txt of a single host
+type robotsEntry struct {
+	once   sync.Once
+	robots *robots.Robots
+}
+
+// robotsCache stores parsed robots.txt files keyed by scheme://host
+type robotsCache struct {
+	entries map[string]*robotsEntry
+	enforce bool // Whether Allowed consults robots.txt and Crawl-delay applies
+	mu      sync.Mutex
+}
+
+// newRobotsCache creates an empty robots.txt cache
+func newRobotsCache() *robotsCache {
+	return &robotsCache{
+		entries: make(map[string]*robotsEntry),
+	}
+}
+
+// EnableRobots turns on robots.txt enforcement for Allowed checks
+// Crawl-delay values found in robots.txt are applied to the per-host rate limiter
+func (c *Client) EnableRobots() {
+	c.robots.mu.Lock()
+	c.robots.enforce = true
+	c.robots.mu.Unlock()
+}
+
+// Allowed reports whether robots.txt permits fetching the URL
+// Always true when robots.txt enforcement is disabled
+func (c *Client) Allowed(rawURL string) bool {
+	c.robots.mu.Lock()
+	enforce := c.robots.enforce
+	c.robots.mu.Unlock()
+
+	if !enforce {
+		return true
+	}
+	return c.RobotsAllows(rawURL)
+}
+
+// RobotsAllows reports whether robots.txt permits fetching the URL,
+// regardless of whether enforcement is enabled (used for auditing)
+func (c *Client) RobotsAllows(rawURL string) bool {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Host == "" {
+		return true
+	}
+
+	return c.robotsFor(parsed).Allowed(c.userAgent, rawURL)
+}
+
+// robotsFor returns the robots.txt rules for a URL's host, fetching them once
+func (c *Client) robotsFor(u *url.URL) *robots.Robots {
+	origin := u.Scheme + "://" + u.Host
+
+	c.robots.mu.Lock()
+	entry, ok := c.robots.entries[origin]
+	if !ok {
+		entry = &robotsEntry{}
+		c.robots.entries[origin] = entry
+	}
+	enforce := c.robots.enforce
+	c.robots.mu.Unlock()
+
+	entry.once.Do(func() {
+		entry.robots = c.fetchRobots(origin)
+
+		// Honor Crawl-delay by throttling this host
+		if delay := entry.robots.CrawlDelay(c.userAgent); enforce && delay > 0 {
+			c.hostLimiter.SetHostRate(u.Host, float64(time.Second)/float64(delay))
+		}
+	})
+
+	return entry.robots
+}
+
+// fetchRobots downloads and parses robots.txt for an origin
+// A missing or unreachable robots.txt is treated as allowing everything
+func (c *Client) fetchRobots(origin string) *robots.Robots {
+	resp := c.Get(origin + "/robots.txt")
+	if resp.Error != nil || resp.StatusCode != 200 {
+		return robots.AllowAll()
+	}
+
+	parsed, err := robots.Parse(bytes.NewReader(resp.Body))
+	if err != nil {
+		return robots.AllowAll()
+	}
+	return parsed
+}
@@ -0,0 +1,113 @@
+package fetcher
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// PageStructure holds the on-page elements used for HTML and SEO checks
+type PageStructure struct {
+	Title            string   // First <title>, whitespace collapsed
+	TitleCount       int      // Number of <title> elements
+	Description      string   // First <meta name="description"> content
+	DescriptionCount int      // Number of description meta tags
+	Lang             string   // lang attribute of <html>
+	H1Count          int      // Number of <h1> elements
+	ImagesWithoutAlt []string // src of <img> elements with no alt attribute
+	EmptyLinks       []string // href of <a> elements with no accessible text
+	UnsafeBlank      []string // href of target=_blank links without rel=noopener
+}
+
+// extractPageStructure collects titles, descriptions, headings and
+// accessibility-relevant attributes from a parsed document
+func extractPageStructure(doc *html.Node) PageStructure {
+	var page PageStructure
+
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			switch n.Data {
+			case "html":
+				page.Lang = strings.TrimSpace(getAttr(n, "lang"))
+			case "title":
+				// <title> inside inline <svg> names the graphic, not the page
+				if !insideSVG(n) {
+					page.TitleCount++
+					if page.TitleCount == 1 {
+						page.Title = strings.Join(strings.Fields(extractText(n)), " ")
+					}
+				}
+			case "meta":
+				if strings.EqualFold(getAttr(n, "name"), "description") {
+					page.DescriptionCount++
+					if page.DescriptionCount == 1 {
+						page.Description = strings.Join(strings.Fields(getAttr(n, "content")), " ")
+					}
+				}
+			case "h1":
+				page.H1Count++
+			case "img":
+				if !hasAttr(n, "alt") {
+					page.ImagesWithoutAlt = append(page.ImagesWithoutAlt, getAttr(n, "src"))
+				}
+			case "a":
+				href := getAttr(n, "href")
+				if href == "" {
+					break
+				}
+				if !hasAccessibleName(n) {
+					page.EmptyLinks = append(page.EmptyLinks, href)
+				}
+				if strings.EqualFold(getAttr(n, "target"), "_blank") {
+					rels := parseRel(getAttr(n, "rel"))
+					// noreferrer implies noopener
+					if !containsRel(rels, "noopener") && !containsRel(rels, "noreferrer") {
+						page.UnsafeBlank = append(page.UnsafeBlank, href)
+					}
+				}
+			}
+		}
+
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+
+	walk(doc)
+	return page
+}
+
+// hasAccessibleName reports whether a link has text, a label, or an image with alt text
+func hasAccessibleName(n *html.Node) bool {
+	if extractText(n) != "" {
+		return true
+	}
+	for _, attr := range []string{"aria-label", "aria-labelledby", "title"} {
+		if strings.TrimSpace(getAttr(n, attr)) != "" {
+			return true
+		}
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode {
+			if c.Data == "img" && strings.TrimSpace(getAttr(c, "alt")) != "" {
+				return true
+			}
+			if hasAccessibleName(c) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// insideSVG reports whether a node is nested in an <svg> element
+func insideSVG(n *html.Node) bool {
+	for p := n.Parent; p != nil; p = p.Parent {
+		if p.Type == html.ElementNode && p.Data == "svg" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,243 @@
+package fetcher
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+)
+
+// TLSInfo describes the certificate an https host presented
+type TLSInfo struct {
+	Host          string   // host[:port] as it appeared in the URL
+	Version       string   // Negotiated protocol, e.g. "TLS 1.3"
+	Issuer        string   // Issuer common name of the leaf certificate
+	Subject       string   // Subject common name of the leaf certificate
+	DNSNames      []string // Subject alternative names
+	NotBefore     time.Time
+	NotAfter      time.Time
+	SelfSigned    bool
+	HostnameMatch bool   // Certificate is valid for Host
+	Verified      bool   // Chain verifies against the system roots
+	VerifyError   string `json:",omitempty"` // Why verification failed
+	Error         string `json:",omitempty"` // Why the certificate couldn't be inspected at all
+}
+
+// DaysUntilExpiry returns the whole days left before NotAfter (negative once expired)
+func (t *TLSInfo) DaysUntilExpiry(now time.Time) int {
+	return int(t.NotAfter.Sub(now).Hours() / 24)
+}
+
+// tlsEntry holds the certificate details of a single host, recorded once
+type tlsEntry struct {
+	once sync.Once
+	info *TLSInfo
+}
+
+// tlsCache stores certificate details keyed by host[:port]
+type tlsCache struct {
+	entries map[string]*tlsEntry
+	enabled bool
+	mu      sync.Mutex
+}
+
+// newTLSCache creates an empty, disabled TLS cache
+func newTLSCache() *tlsCache {
+	return &tlsCache{
+		entries: make(map[string]*tlsEntry),
+	}
+}
+
+// entry returns the cache entry for a host, or nil when inspection is disabled
+func (tc *tlsCache) entry(host string) *tlsEntry {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	if !tc.enabled {
+		return nil
+	}
+	e, ok := tc.entries[host]
+	if !ok {
+		e = &tlsEntry{}
+		tc.entries[host] = e
+	}
+	return e
+}
+
+// EnableTLSInspection records certificate details for every https host contacted
+// Hosts that fail the handshake are re-dialed without verification to see why
+func (c *Client) EnableTLSInspection() {
+	c.tls.mu.Lock()
+	c.tls.enabled = true
+	c.tls.mu.Unlock()
+}
+
+// TLSHosts returns the certificate details recorded so far, sorted by host
+// Call once requests have finished; in-flight inspections are not waited for
+func (c *Client) TLSHosts() []TLSInfo {
+	c.tls.mu.Lock()
+	hosts := make([]TLSInfo, 0, len(c.tls.entries))
+	for _, e := range c.tls.entries {
+		if e.info != nil {
+			hosts = append(hosts, *e.info)
+		}
+	}
+	c.tls.mu.Unlock()
+
+	sort.Slice(hosts, func(i, j int) bool {
+		return hosts[i].Host < hosts[j].Host
+	})
+	return hosts
+}
+
+// recordTLS stores the certificate of a successful https response
+func (c *Client) recordTLS(resp *http.Response) *TLSInfo {
+	if resp.TLS == nil || len(resp.TLS.PeerCertificates) == 0 {
+		return nil
+	}
+
+	e := c.tls.entry(resp.Request.URL.Host)
+	if e == nil {
+		return nil
+	}
+	e.once.Do(func() {
+		e.info = newTLSInfo(resp.Request.URL.Host, *resp.TLS)
+		e.info.HostnameMatch = true
+		e.info.Verified = true
+	})
+	return e.info
+}
+
+// inspectTLSFailure re-dials a host whose certificate failed verification and
+// records what was wrong with it; returns nil for errors unrelated to certificates
+func (c *Client) inspectTLSFailure(rawURL string, err error) *TLSInfo {
+	if err == nil || !IsCertificateError(err) {
+		return nil
+	}
+
+	// The failure may have happened on a redirect hop, not the original URL
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		rawURL = urlErr.URL
+	}
+	parsed, perr := url.Parse(rawURL)
+	if perr != nil || parsed.Host == "" {
+		return nil
+	}
+
+	e := c.tls.entry(parsed.Host)
+	if e == nil {
+		return nil
+	}
+	e.once.Do(func() {
+		e.info = c.dialTLS(parsed)
+	})
+	return e.info
+}
+
+// inspectTLS records the certificate of an https URL fetched outside the
+// HTTP client, such as by the Chrome renderer
+func (c *Client) inspectTLS(rawURL string) *TLSInfo {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Scheme != "https" || parsed.Host == "" {
+		return nil
+	}
+
+	e := c.tls.entry(parsed.Host)
+	if e == nil {
+		return nil
+	}
+	e.once.Do(func() {
+		e.info = c.dialTLS(parsed)
+	})
+	return e.info
+}
+
+// dialTLS connects without verification to read the presented certificate,
+// then verifies it by hand so every problem is recorded, not just the first
+func (c *Client) dialTLS(target *url.URL) *TLSInfo {
+	hostname := target.Hostname()
+	addr := target.Host
+	if target.Port() == "" {
+		addr = net.JoinHostPort(hostname, "443")
+	}
+
+	dialer := &net.Dialer{Timeout: c.httpClient.Timeout}
+	conn, err := tls.DialWithDialer(dialer, "tcp", addr, &tls.Config{
+		ServerName:         hostname,
+		InsecureSkipVerify: true, // Inspection only; nothing is sent over this connection
+	})
+	if err != nil {
+		return &TLSInfo{Host: target.Host, Error: err.Error()}
+	}
+	defer conn.Close()
+
+	state := conn.ConnectionState()
+	if len(state.PeerCertificates) == 0 {
+		return &TLSInfo{Host: target.Host, Error: "no certificate presented"}
+	}
+
+	info := newTLSInfo(target.Host, state)
+	leaf := state.PeerCertificates[0]
+	info.HostnameMatch = leaf.VerifyHostname(hostname) == nil
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range state.PeerCertificates[1:] {
+		intermediates.AddCert(cert)
+	}
+	if _, err := leaf.Verify(x509.VerifyOptions{DNSName: hostname, Intermediates: intermediates}); err != nil {
+		info.VerifyError = err.Error()
+	} else {
+		info.Verified = true
+	}
+
+	return info
+}
+
+// newTLSInfo builds the details shared by successful and failed handshakes
+func newTLSInfo(host string, state tls.ConnectionState) *TLSInfo {
+	leaf := state.PeerCertificates[0]
+
+	issuer := leaf.Issuer.CommonName
+	if issuer == "" {
+		issuer = leaf.Issuer.String()
+	}
+
+	return &TLSInfo{
+		Host:       host,
+		Version:    tls.VersionName(state.Version),
+		Issuer:     issuer,
+		Subject:    leaf.Subject.CommonName,
+		DNSNames:   leaf.DNSNames,
+		NotBefore:  leaf.NotBefore,
+		NotAfter:   leaf.NotAfter,
+		SelfSigned: isSelfSigned(leaf),
+	}
+}
+
+// isSelfSigned reports whether a certificate is signed by its own key
+// CheckSignatureFrom isn't used since it rejects parents that aren't CAs,
+// and most self-signed server certificates aren't
+func isSelfSigned(cert *x509.Certificate) bool {
+	return bytes.Equal(cert.RawIssuer, cert.RawSubject) &&
+		cert.CheckSignature(cert.SignatureAlgorithm, cert.RawTBSCertificate, cert.Signature) == nil
+}
+
+// IsCertificateError reports whether a request failed because of the server certificate
+func IsCertificateError(err error) bool {
+	var verifyErr *tls.CertificateVerificationError
+	var unknownAuthority x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var invalidErr x509.CertificateInvalidError
+
+	return errors.As(err, &verifyErr) ||
+		errors.As(err, &unknownAuthority) ||
+		errors.As(err, &hostnameErr) ||
+		errors.As(err, &invalidErr)
+}
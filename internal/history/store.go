@@ -0,0 +1,162 @@
+package history
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	_ "modernc.org/sqlite" // Pure Go SQLite driver
+
+	"linkchex/internal/validator"
+)
+
+// schema creates the tables on first use; existing databases are left as is
+const schema = `
+CREATE TABLE IF NOT EXISTS runs (
+	id            INTEGER PRIMARY KEY AUTOINCREMENT,
+	started_at    INTEGER NOT NULL, -- Unix seconds
+	duration_ms   INTEGER NOT NULL,
+	config        TEXT    NOT NULL, -- JSON
+	pages         INTEGER NOT NULL,
+	total_links   INTEGER NOT NULL,
+	broken_links  INTEGER NOT NULL,
+	warning_links INTEGER NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS results (
+	run_id         INTEGER NOT NULL REFERENCES runs(id) ON DELETE CASCADE,
+	source_url     TEXT    NOT NULL,
+	target_url     TEXT    NOT NULL,
+	status_code    INTEGER NOT NULL,
+	status         TEXT    NOT NULL,
+	error          TEXT    NOT NULL,
+	is_external    INTEGER NOT NULL,
+	tag            TEXT    NOT NULL,
+	link_text      TEXT    NOT NULL,
+	duration_ms    INTEGER NOT NULL,
+	is_broken      INTEGER NOT NULL,
+	soft_404       INTEGER NOT NULL,
+	content_type   TEXT    NOT NULL,
+	content_length INTEGER NOT NULL,
+	oversize       INTEGER NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS results_target ON results (target_url, run_id);
+CREATE INDEX IF NOT EXISTS results_run ON results (run_id);
+`
+
+// Store records validation runs and their results in a SQLite database
+type Store struct {
+	db *sql.DB
+}
+
+// Run is one recorded validation run
+type Run struct {
+	ID           int64
+	StartedAt    time.Time
+	Duration     time.Duration
+	Config       json.RawMessage // Run configuration as recorded
+	Pages        int
+	TotalLinks   int
+	BrokenLinks  int
+	WarningLinks int
+}
+
+// Open opens or creates a history database
+func Open(path string) (*Store, error) {
+	db, err := sql.Open("sqlite", path+"?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history database: %w", err)
+	}
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize history database: %w", err)
+	}
+	return &Store{db: db}, nil
+}
+
+// Close closes the database
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// RecordRun stores a run's summary, configuration and every result,
+// returning the new run ID
+func (s *Store) RecordRun(report *validator.ValidationReport, config any) (int64, error) {
+	configJSON, err := json.Marshal(config)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode run config: %w", err)
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`INSERT INTO runs (started_at, duration_ms, config, pages, total_links, broken_links, warning_links)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		report.StartTime.Unix(), report.Duration.Milliseconds(), string(configJSON),
+		report.PagesProcessed, report.TotalLinks, report.BrokenLinks, report.WarningLinks)
+	if err != nil {
+		return 0, fmt.Errorf("failed to record run: %w", err)
+	}
+	runID, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	stmt, err := tx.Prepare(`INSERT INTO results (run_id, source_url, target_url, status_code, status, error,
+		is_external, tag, link_text, duration_ms, is_broken, soft_404, content_type, content_length, oversize)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+
+	for _, result := range report.Results {
+		errorStr := ""
+		if result.Error != nil {
+			errorStr = result.Error.Error()
+		}
+		if _, err := stmt.Exec(runID, result.SourceURL, result.TargetURL, result.StatusCode, result.Status, errorStr,
+			result.IsExternal, result.Tag, result.LinkText, result.Duration.Milliseconds(), result.IsBroken,
+			result.Soft404, result.ContentType, result.ContentLength, result.Oversize); err != nil {
+			return 0, fmt.Errorf("failed to record result: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to record run: %w", err)
+	}
+	return runID, nil
+}
+
+// Runs returns the most recent runs, newest first (limit <= 0 = all)
+func (s *Store) Runs(limit int) ([]Run, error) {
+	if limit <= 0 {
+		limit = -1 // SQLite: no limit
+	}
+	rows, err := s.db.Query(`SELECT id, started_at, duration_ms, config, pages, total_links, broken_links, warning_links
+		FROM runs ORDER BY id DESC LIMIT ?`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var runs []Run
+	for rows.Next() {
+		var run Run
+		var startedAt, durationMS int64
+		var config string
+		if err := rows.Scan(&run.ID, &startedAt, &durationMS, &config, &run.Pages, &run.TotalLinks, &run.BrokenLinks, &run.WarningLinks); err != nil {
+			return nil, err
+		}
+		run.Config = json.RawMessage(config)
+		run.StartedAt = time.Unix(startedAt, 0)
+		run.Duration = time.Duration(durationMS) * time.Millisecond
+		runs = append(runs, run)
+	}
+	return runs, rows.Err()
+}
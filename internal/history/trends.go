@@ -0,0 +1,210 @@
+package history
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Observation is the state of one link target in one run
+type Observation struct {
+	RunID      int64
+	At         time.Time
+	TargetURL  string
+	Broken     bool
+	StatusCode int
+	Status     string
+	Error      string
+}
+
+// BrokenLink is a link target broken in the latest run, with how long it
+// has been broken
+type BrokenLink struct {
+	TargetURL    string
+	StatusCode   int
+	Status       string
+	Error        string
+	BrokenSince  time.Time // First run of the current broken streak
+	FirstBroken  time.Time // First run it was ever broken
+	LastOK       time.Time // Last run it worked, zero if never
+	BrokenRuns   int       // Runs in the current streak
+	BrokenFor    time.Duration
+	SourcePages  int // Pages linking to it in the latest run
+	Observations int // Runs it was checked in
+}
+
+// FlakyLink is a link target that alternated between working and broken
+type FlakyLink struct {
+	TargetURL  string
+	Flips      int // Changes between ok and broken
+	BrokenRuns int
+	Runs       int // Runs it was checked in, within the window
+	Last       Observation
+}
+
+// observationQuery reduces results to one row per run and target, taking a
+// broken occurrence over a working one so status and error stay consistent
+// Skipped links (excluded or disallowed) say nothing about a link's health
+const observationQuery = `
+SELECT run_id, started_at, target_url, is_broken, status_code, status, error
+FROM (
+	SELECT r.run_id, runs.started_at, r.target_url, r.is_broken, r.status_code, r.status, r.error,
+		ROW_NUMBER() OVER (PARTITION BY r.run_id, r.target_url ORDER BY r.is_broken DESC, r.rowid) AS n
+	FROM results r JOIN runs ON runs.id = r.run_id
+	WHERE r.status NOT LIKE 'Skipped%%' AND %s
+)
+WHERE n = 1
+ORDER BY target_url, run_id`
+
+// observations loads per-run observations matching a WHERE condition,
+// grouped by target in run order
+func (s *Store) observations(where string, args ...any) (map[string][]Observation, []string, error) {
+	rows, err := s.db.Query(fmt.Sprintf(observationQuery, where), args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	byTarget := make(map[string][]Observation)
+	var targets []string
+	for rows.Next() {
+		var obs Observation
+		var startedAt int64
+		if err := rows.Scan(&obs.RunID, &startedAt, &obs.TargetURL, &obs.Broken, &obs.StatusCode, &obs.Status, &obs.Error); err != nil {
+			return nil, nil, err
+		}
+		obs.At = time.Unix(startedAt, 0)
+		if _, ok := byTarget[obs.TargetURL]; !ok {
+			targets = append(targets, obs.TargetURL)
+		}
+		byTarget[obs.TargetURL] = append(byTarget[obs.TargetURL], obs)
+	}
+	return byTarget, targets, rows.Err()
+}
+
+// LinkTimeline returns every recorded observation of one link target, oldest first
+func (s *Store) LinkTimeline(targetURL string) ([]Observation, error) {
+	byTarget, _, err := s.observations("r.target_url = ?", targetURL)
+	if err != nil {
+		return nil, err
+	}
+	return byTarget[targetURL], nil
+}
+
+// BrokenLinks returns the targets broken in the latest run, longest broken first
+func (s *Store) BrokenLinks() ([]BrokenLink, error) {
+	var latest int64
+	var latestAt int64
+	err := s.db.QueryRow(`SELECT id, started_at FROM runs ORDER BY id DESC LIMIT 1`).Scan(&latest, &latestAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil // No runs recorded yet
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	byTarget, targets, err := s.observations(
+		"r.target_url IN (SELECT target_url FROM results WHERE run_id = ? AND is_broken = 1)", latest)
+	if err != nil {
+		return nil, err
+	}
+
+	sources := make(map[string]int)
+	rows, err := s.db.Query(`SELECT target_url, COUNT(DISTINCT source_url) FROM results
+		WHERE run_id = ? AND is_broken = 1 GROUP BY target_url`, latest)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var target string
+		var count int
+		if err := rows.Scan(&target, &count); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		sources[target] = count
+	}
+	rows.Close()
+
+	links := make([]BrokenLink, 0, len(targets))
+	for _, target := range targets {
+		history := byTarget[target]
+		last := history[len(history)-1]
+		link := BrokenLink{
+			TargetURL:    target,
+			StatusCode:   last.StatusCode,
+			Status:       last.Status,
+			Error:        last.Error,
+			SourcePages:  sources[target],
+			Observations: len(history),
+		}
+
+		// Walk back from the latest run to the start of the current streak
+		for i := len(history) - 1; i >= 0; i-- {
+			if !history[i].Broken {
+				link.LastOK = history[i].At
+				break
+			}
+			link.BrokenSince = history[i].At
+			link.BrokenRuns++
+		}
+		for _, obs := range history {
+			if obs.Broken {
+				link.FirstBroken = obs.At
+				break
+			}
+		}
+		link.BrokenFor = time.Unix(latestAt, 0).Sub(link.BrokenSince)
+		links = append(links, link)
+	}
+
+	sort.SliceStable(links, func(i, j int) bool {
+		if !links[i].BrokenSince.Equal(links[j].BrokenSince) {
+			return links[i].BrokenSince.Before(links[j].BrokenSince)
+		}
+		return links[i].TargetURL < links[j].TargetURL
+	})
+	return links, nil
+}
+
+// FlakyLinks returns targets that changed between ok and broken at least
+// minFlips times within the last window runs, most changes first
+func (s *Store) FlakyLinks(window, minFlips int) ([]FlakyLink, error) {
+	var firstRun int64
+	err := s.db.QueryRow(`SELECT COALESCE(MIN(id), 0) FROM (SELECT id FROM runs ORDER BY id DESC LIMIT ?)`, window).Scan(&firstRun)
+	if err != nil {
+		return nil, err
+	}
+
+	byTarget, targets, err := s.observations("r.run_id >= ?", firstRun)
+	if err != nil {
+		return nil, err
+	}
+
+	var flaky []FlakyLink
+	for _, target := range targets {
+		history := byTarget[target]
+		link := FlakyLink{TargetURL: target, Runs: len(history), Last: history[len(history)-1]}
+		for i, obs := range history {
+			if obs.Broken {
+				link.BrokenRuns++
+			}
+			if i > 0 && obs.Broken != history[i-1].Broken {
+				link.Flips++
+			}
+		}
+		if link.Flips >= minFlips {
+			flaky = append(flaky, link)
+		}
+	}
+
+	sort.SliceStable(flaky, func(i, j int) bool {
+		if flaky[i].Flips != flaky[j].Flips {
+			return flaky[i].Flips > flaky[j].Flips
+		}
+		return flaky[i].TargetURL < flaky[j].TargetURL
+	})
+	return flaky, nil
+}
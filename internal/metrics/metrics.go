@@ -0,0 +1,272 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultLatencyBuckets are histogram upper bounds in seconds for request latency
+var DefaultLatencyBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Registry holds metric families and renders them in the Prometheus text
+// exposition format, for scraping or for node_exporter's textfile collector
+type Registry struct {
+	mu       sync.Mutex
+	families []*family
+}
+
+// family is one named metric with its series, keyed by label values
+type family struct {
+	name       string
+	help       string
+	kind       string // counter, gauge or histogram
+	labelNames []string
+	buckets    []float64      // Histogram upper bounds, ascending
+	value      func() float64 // Computed at render time (gauge funcs only)
+	mu         sync.Mutex
+	series     map[string]*series
+}
+
+// series is one combination of label values
+type series struct {
+	labelValues []string
+	value       float64  // Counter or gauge value
+	counts      []uint64 // Per-bucket (non-cumulative) histogram counts
+	sum         float64
+	count       uint64
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Counter is a monotonically increasing metric
+type Counter struct{ f *family }
+
+// Gauge is a metric that can go up and down
+type Gauge struct{ f *family }
+
+// Histogram counts observations into buckets
+type Histogram struct{ f *family }
+
+// Counter registers a counter with the given label names
+func (r *Registry) Counter(name, help string, labelNames ...string) *Counter {
+	return &Counter{r.register(name, help, "counter", labelNames, nil)}
+}
+
+// Gauge registers a gauge with the given label names
+func (r *Registry) Gauge(name, help string, labelNames ...string) *Gauge {
+	return &Gauge{r.register(name, help, "gauge", labelNames, nil)}
+}
+
+// GaugeFunc registers an unlabeled gauge whose value is computed when rendered
+func (r *Registry) GaugeFunc(name, help string, value func() float64) {
+	f := r.register(name, help, "gauge", nil, nil)
+	f.value = value
+}
+
+// Histogram registers a histogram with the given bucket upper bounds
+func (r *Registry) Histogram(name, help string, buckets []float64, labelNames ...string) *Histogram {
+	sorted := append([]float64(nil), buckets...)
+	sort.Float64s(sorted)
+	return &Histogram{r.register(name, help, "histogram", labelNames, sorted)}
+}
+
+// register adds a metric family, or returns the existing one with that name
+func (r *Registry) register(name, help, kind string, labelNames []string, buckets []float64) *family {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, f := range r.families {
+		if f.name == name {
+			return f
+		}
+	}
+	f := &family{
+		name:       name,
+		help:       help,
+		kind:       kind,
+		labelNames: labelNames,
+		buckets:    buckets,
+		series:     make(map[string]*series),
+	}
+	r.families = append(r.families, f)
+	return f
+}
+
+// Inc adds 1 to the counter for the given label values
+func (c *Counter) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add adds a non-negative amount to the counter for the given label values
+func (c *Counter) Add(delta float64, labelValues ...string) {
+	if delta < 0 {
+		return
+	}
+	c.f.update(labelValues, func(s *series) { s.value += delta })
+}
+
+// Set sets the gauge for the given label values
+func (g *Gauge) Set(value float64, labelValues ...string) {
+	g.f.update(labelValues, func(s *series) { s.value = value })
+}
+
+// Observe records one observation for the given label values
+func (h *Histogram) Observe(value float64, labelValues ...string) {
+	h.f.update(labelValues, func(s *series) {
+		if s.counts == nil {
+			s.counts = make([]uint64, len(h.f.buckets))
+		}
+		if i := sort.SearchFloat64s(h.f.buckets, value); i < len(h.f.buckets) {
+			s.counts[i]++
+		}
+		s.sum += value
+		s.count++
+	})
+}
+
+// update applies fn to the series for labelValues, creating it if needed
+func (f *family) update(labelValues []string, fn func(*series)) {
+	if len(labelValues) != len(f.labelNames) {
+		panic(fmt.Sprintf("metrics: %s expects %d label values, got %d", f.name, len(f.labelNames), len(labelValues)))
+	}
+	key := strings.Join(labelValues, "\xff")
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	s, ok := f.series[key]
+	if !ok {
+		s = &series{labelValues: append([]string(nil), labelValues...)}
+		f.series[key] = s
+	}
+	fn(s)
+}
+
+// WriteText writes every metric in the Prometheus text exposition format
+func (r *Registry) WriteText(w io.Writer) error {
+	r.mu.Lock()
+	families := append([]*family(nil), r.families...)
+	r.mu.Unlock()
+
+	bw := bufio.NewWriter(w)
+	for _, f := range families {
+		// Computed before locking since it may read other metrics
+		var computed float64
+		if f.value != nil {
+			computed = f.value()
+		}
+
+		fmt.Fprintf(bw, "# HELP %s %s\n", f.name, escapeHelp(f.help))
+		fmt.Fprintf(bw, "# TYPE %s %s\n", f.name, f.kind)
+
+		if f.value != nil {
+			fmt.Fprintf(bw, "%s %s\n", f.name, formatValue(computed))
+			continue
+		}
+
+		f.mu.Lock()
+		keys := make([]string, 0, len(f.series))
+		for key := range f.series {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			s := f.series[key]
+			if f.kind != "histogram" {
+				fmt.Fprintf(bw, "%s%s %s\n", f.name, formatLabels(f.labelNames, s.labelValues, "", ""), formatValue(s.value))
+				continue
+			}
+
+			var cumulative uint64
+			for i, upper := range f.buckets {
+				cumulative += s.counts[i]
+				fmt.Fprintf(bw, "%s_bucket%s %d\n", f.name, formatLabels(f.labelNames, s.labelValues, "le", formatValue(upper)), cumulative)
+			}
+			fmt.Fprintf(bw, "%s_bucket%s %d\n", f.name, formatLabels(f.labelNames, s.labelValues, "le", "+Inf"), s.count)
+			fmt.Fprintf(bw, "%s_sum%s %s\n", f.name, formatLabels(f.labelNames, s.labelValues, "", ""), formatValue(s.sum))
+			fmt.Fprintf(bw, "%s_count%s %d\n", f.name, formatLabels(f.labelNames, s.labelValues, "", ""), s.count)
+		}
+		f.mu.Unlock()
+	}
+	return bw.Flush()
+}
+
+// ServeHTTP serves the metrics for Prometheus to scrape
+func (r *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	r.WriteText(w)
+}
+
+// WriteFile writes the metrics to a file for node_exporter's textfile
+// collector. The file is written to a temporary name and renamed so the
+// collector never reads a partial file
+func (r *Registry) WriteFile(filename string) error {
+	tmp, err := os.CreateTemp(filepath.Dir(filename), "."+filepath.Base(filename)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := r.WriteText(tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(0644); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filename)
+}
+
+// formatLabels renders {name="value",...}, with an optional extra label
+func formatLabels(names, values []string, extraName, extraValue string) string {
+	if len(names) == 0 && extraName == "" {
+		return ""
+	}
+
+	pairs := make([]string, 0, len(names)+1)
+	for i, name := range names {
+		pairs = append(pairs, name+`="`+escapeLabel(values[i])+`"`)
+	}
+	if extraName != "" {
+		pairs = append(pairs, extraName+`="`+extraValue+`"`)
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// formatValue renders a sample value the way Prometheus expects
+func formatValue(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// escapeLabel escapes a label value
+func escapeLabel(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
+
+// escapeHelp escapes HELP text
+func escapeHelp(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(s)
+}
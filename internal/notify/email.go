@@ -0,0 +1,51 @@
+package notify
+
+import (
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// Email sends the message over SMTP, upgrading to TLS with STARTTLS when
+// the server offers it
+type Email struct {
+	Server   string // host:port
+	From     string
+	To       []string
+	Username string // Empty for no authentication
+	Password string
+}
+
+// Name identifies the notifier in errors
+func (e *Email) Name() string {
+	return "email via " + e.Server
+}
+
+// Send mails the message as plain text
+func (e *Email) Send(msg *Message) error {
+	var auth smtp.Auth
+	if e.Username != "" {
+		host, _, err := net.SplitHostPort(e.Server)
+		if err != nil {
+			return fmt.Errorf("invalid SMTP server %q: %w", e.Server, err)
+		}
+		auth = smtp.PlainAuth("", e.Username, e.Password, host)
+	}
+
+	var sb strings.Builder
+	sb.WriteString("From: " + e.From + "\r\n")
+	sb.WriteString("To: " + strings.Join(e.To, ", ") + "\r\n")
+	sb.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", msg.Title) + "\r\n")
+	sb.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	sb.WriteString("MIME-Version: 1.0\r\n")
+	sb.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	sb.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	sb.WriteString("\r\n")
+	body := msg.Text(func(url string) string { return url })
+	sb.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+
+	return smtp.SendMail(e.Server, auth, e.From, e.To, []byte(sb.String()))
+}
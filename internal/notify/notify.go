@@ -0,0 +1,236 @@
+package notify
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"linkchex/internal/validator"
+)
+
+// Conditions for sending a notification
+const (
+	OnAlways = "always" // Every run
+	OnBroken = "broken" // Runs with broken links
+	OnNew    = "new"    // Runs with links broken since the last notification
+)
+
+// DefaultTop is how many broken links a notification lists by default
+const DefaultTop = 10
+
+// Notifier delivers a message to one destination
+type Notifier interface {
+	Name() string
+	Send(msg *Message) error
+}
+
+// Message is a run summary with its top broken links
+type Message struct {
+	Title         string
+	Site          string
+	StartTime     time.Time
+	Duration      time.Duration
+	Pages         int
+	TotalLinks    int
+	BrokenLinks   int
+	WarningLinks  int
+	FlakyLinks    int
+	BrokenTargets int          // Distinct broken URLs
+	NewBroken     int          // Broken URLs not yet notified
+	TopBroken     []BrokenLink // New ones first, then most widespread
+	ReportURL     string       `json:",omitempty"`
+}
+
+// BrokenLink is a broken URL as listed in a notification
+type BrokenLink struct {
+	URL         string
+	Status      string
+	Pages       int // Distinct source pages
+	Occurrences int
+	New         bool // Not broken in the last notification
+}
+
+// Options controls when notifications are sent
+type Options struct {
+	On        string        // OnAlways, OnBroken or OnNew
+	Throttle  time.Duration // Minimum time between notifications (0 = no limit)
+	StateFile string        // Remembers notified links and the last send time
+	Top       int           // Broken links to list
+	Site      string
+	ReportURL string // Link to the full report, if published somewhere
+}
+
+// Validate checks that the options are usable
+func (o Options) Validate() error {
+	switch o.On {
+	case OnAlways, OnBroken, OnNew:
+	default:
+		return fmt.Errorf("unknown notify condition %q (use always, broken or new)", o.On)
+	}
+	if o.StateFile == "" && (o.On == OnNew || o.Throttle > 0) {
+		return errors.New("notifying on new breakages or throttling needs a state file")
+	}
+	return nil
+}
+
+// Result describes what Notify did
+type Result struct {
+	Sent   bool
+	Reason string // Why nothing was sent
+	Failed int    // Notifiers that returned an error
+}
+
+// Notify sends the report summary to every notifier if the run meets the
+// conditions in opts. Notifier failures are joined into the returned error;
+// the state is updated as long as at least one notifier succeeded
+func Notify(report *validator.ValidationReport, notifiers []Notifier, opts Options) (Result, error) {
+	state := NewState()
+	if opts.StateFile != "" {
+		var err error
+		if state, err = LoadState(opts.StateFile); err != nil {
+			return Result{}, err
+		}
+	}
+
+	now := time.Now()
+	msg := NewMessage(report, state, opts)
+	state.forgetFixed(report.BrokenTargets)
+
+	skip := ""
+	switch {
+	case opts.On == OnBroken && msg.BrokenTargets == 0:
+		skip = "no broken links"
+	case opts.On == OnNew && msg.NewBroken == 0:
+		skip = "no new broken links"
+	case opts.Throttle > 0 && !state.LastSent.IsZero() && now.Sub(state.LastSent) < opts.Throttle:
+		skip = fmt.Sprintf("throttled, last notification sent %s ago", now.Sub(state.LastSent).Round(time.Second))
+	}
+	if skip != "" {
+		// Fixed links are still forgotten, so they count as new if they break again
+		return Result{Reason: skip}, saveState(state, opts.StateFile)
+	}
+
+	var errs []error
+	for _, notifier := range notifiers {
+		if err := notifier.Send(msg); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", notifier.Name(), err))
+		}
+	}
+	result := Result{Failed: len(errs), Sent: len(errs) < len(notifiers)}
+
+	if result.Sent {
+		state.LastSent = now
+		for _, target := range report.BrokenTargets {
+			if _, ok := state.Notified[target.Canonical]; !ok {
+				state.Notified[target.Canonical] = now
+			}
+		}
+	}
+	if err := saveState(state, opts.StateFile); err != nil {
+		errs = append(errs, err)
+	}
+	return result, errors.Join(errs...)
+}
+
+// NewMessage summarizes a report, marking broken links the state hasn't
+// notified yet as new. Without a state file nothing is new
+func NewMessage(report *validator.ValidationReport, state *State, opts Options) *Message {
+	msg := &Message{
+		Site:          opts.Site,
+		StartTime:     report.StartTime,
+		Duration:      report.Duration,
+		Pages:         report.PagesProcessed,
+		TotalLinks:    report.TotalLinks,
+		BrokenLinks:   report.BrokenLinks,
+		WarningLinks:  report.WarningLinks,
+		FlakyLinks:    report.FlakyLinks,
+		BrokenTargets: len(report.BrokenTargets),
+		ReportURL:     opts.ReportURL,
+	}
+
+	links := make([]BrokenLink, 0, len(report.BrokenTargets))
+	for _, target := range report.BrokenTargets {
+		_, notified := state.Notified[target.Canonical]
+		link := BrokenLink{
+			URL:         target.URL,
+			Status:      targetStatus(target),
+			Pages:       target.Pages,
+			Occurrences: len(target.Occurrences),
+			New:         opts.StateFile != "" && !notified,
+		}
+		if link.New {
+			msg.NewBroken++
+		}
+		links = append(links, link)
+	}
+
+	// BrokenTargets is already most widespread first
+	sort.SliceStable(links, func(i, j int) bool {
+		return links[i].New && !links[j].New
+	})
+	top := opts.Top
+	if top <= 0 {
+		top = DefaultTop
+	}
+	if len(links) > top {
+		links = links[:top]
+	}
+	msg.TopBroken = links
+
+	site := msg.Site
+	if site == "" {
+		site = "link check"
+	}
+	switch {
+	case msg.BrokenTargets == 0:
+		msg.Title = fmt.Sprintf("✅ Linkchex: no broken links on %s", site)
+	case msg.NewBroken > 0:
+		msg.Title = fmt.Sprintf("❌ Linkchex: %d new broken link(s) on %s (%d total)", msg.NewBroken, site, msg.BrokenTargets)
+	default:
+		msg.Title = fmt.Sprintf("❌ Linkchex: %d broken link(s) on %s", msg.BrokenTargets, site)
+	}
+
+	return msg
+}
+
+// Text renders the message as plain text, formatting each URL with link
+func (m *Message) Text(link func(url string) string) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Pages: %d | Links: %d | Broken: %d | Warnings: %d", m.Pages, m.TotalLinks, m.BrokenLinks, m.WarningLinks))
+	if m.FlakyLinks > 0 {
+		sb.WriteString(fmt.Sprintf(" | Flaky: %d", m.FlakyLinks))
+	}
+	sb.WriteString(fmt.Sprintf("\nDuration: %s\n", m.Duration.Round(time.Millisecond)))
+
+	if len(m.TopBroken) > 0 {
+		sb.WriteString("\nBroken links:\n")
+		for _, b := range m.TopBroken {
+			marker := ""
+			if b.New {
+				marker = " [new]"
+			}
+			sb.WriteString(fmt.Sprintf("• %s — %s, on %d page(s)%s\n", link(b.URL), b.Status, b.Pages, marker))
+		}
+		if more := m.BrokenTargets - len(m.TopBroken); more > 0 {
+			sb.WriteString(fmt.Sprintf("…and %d more\n", more))
+		}
+	}
+
+	if m.ReportURL != "" {
+		sb.WriteString(fmt.Sprintf("\nFull report: %s\n", link(m.ReportURL)))
+	}
+	return sb.String()
+}
+
+// targetStatus describes why a broken target failed
+func targetStatus(target validator.BrokenTarget) string {
+	if target.Error != "" {
+		return target.Error
+	}
+	if strings.HasPrefix(target.Status, fmt.Sprint(target.StatusCode)) {
+		return target.Status
+	}
+	return fmt.Sprintf("%d %s", target.StatusCode, target.Status)
+}
@@ -0,0 +1,96 @@
+package notify
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"linkchex/internal/validator"
+)
+
+// State remembers which broken links have been notified and when the last
+// notification went out
+type State struct {
+	LastSent time.Time            `json:"last_sent"`
+	Notified map[string]time.Time `json:"notified"` // Canonical broken URL -> first notified
+}
+
+// NewState creates an empty state
+func NewState() *State {
+	return &State{Notified: make(map[string]time.Time)}
+}
+
+// LoadState reads a state file, returning an empty state if it doesn't exist yet
+func LoadState(path string) (*State, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return NewState(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read notify state file: %w", err)
+	}
+
+	state := NewState()
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("failed to parse notify state file: %w", err)
+	}
+	if state.Notified == nil {
+		state.Notified = make(map[string]time.Time)
+	}
+	return state, nil
+}
+
+// Save writes the state to a file, replacing it atomically
+func (s *State) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(0644); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// forgetFixed drops notified links that are no longer broken, so they count
+// as new if they break again
+func (s *State) forgetFixed(targets []validator.BrokenTarget) {
+	broken := make(map[string]bool, len(targets))
+	for _, target := range targets {
+		broken[target.Canonical] = true
+	}
+	for url := range s.Notified {
+		if !broken[url] {
+			delete(s.Notified, url)
+		}
+	}
+}
+
+// saveState saves the state if a state file is configured
+func saveState(state *State, path string) error {
+	if path == "" {
+		return nil
+	}
+	if err := state.Save(path); err != nil {
+		return fmt.Errorf("failed to write notify state file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// webhookTimeout bounds each webhook request
+const webhookTimeout = 15 * time.Second
+
+// Webhook posts the message as JSON to a URL, either as is or rendered
+// through a body template
+type Webhook struct {
+	URL      string
+	template *template.Template
+	client   *http.Client
+}
+
+// NewWebhook creates a generic webhook notifier
+func NewWebhook(url string) *Webhook {
+	return &Webhook{URL: url, client: &http.Client{Timeout: webhookTimeout}}
+}
+
+// LoadTemplate renders request bodies through a Go template file, with the
+// Message as data. The json function encodes a value, e.g. {{json .Title}}
+func (w *Webhook) LoadTemplate(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read webhook template: %w", err)
+	}
+	tmpl, err := template.New(path).Funcs(template.FuncMap{
+		"json": func(v any) (string, error) {
+			data, err := json.Marshal(v)
+			return string(data), err
+		},
+	}).Parse(string(data))
+	if err != nil {
+		return fmt.Errorf("failed to parse webhook template: %w", err)
+	}
+	w.template = tmpl
+	return nil
+}
+
+// Name identifies the notifier in errors
+func (w *Webhook) Name() string {
+	return "webhook " + RedactURL(w.URL)
+}
+
+// Send posts the message
+func (w *Webhook) Send(msg *Message) error {
+	if w.template == nil {
+		body, err := json.Marshal(msg)
+		if err != nil {
+			return err
+		}
+		return postJSON(w.client, w.URL, body)
+	}
+
+	var buf bytes.Buffer
+	if err := w.template.Execute(&buf, msg); err != nil {
+		return fmt.Errorf("failed to render webhook template: %w", err)
+	}
+	if !json.Valid(buf.Bytes()) {
+		return fmt.Errorf("webhook template did not produce valid JSON")
+	}
+	return postJSON(w.client, w.URL, buf.Bytes())
+}
+
+// Slack posts to a Slack-compatible incoming webhook (Slack, Mattermost,
+// Rocket.Chat, Discord's /slack endpoint)
+type Slack struct {
+	URL    string
+	client *http.Client
+}
+
+// NewSlack creates a Slack incoming webhook notifier
+func NewSlack(url string) *Slack {
+	return &Slack{URL: url, client: &http.Client{Timeout: webhookTimeout}}
+}
+
+// Name identifies the notifier in errors
+func (s *Slack) Name() string {
+	return "slack " + RedactURL(s.URL)
+}
+
+// Send posts the message as Slack mrkdwn
+func (s *Slack) Send(msg *Message) error {
+	text := fmt.Sprintf("*%s*\n%s", slackEscape(msg.Title), msg.Text(func(url string) string {
+		return fmt.Sprintf("<%s|%s>", url, slackEscape(url))
+	}))
+	body, err := json.Marshal(map[string]string{"text": text})
+	if err != nil {
+		return err
+	}
+	return postJSON(s.client, s.URL, body)
+}
+
+// Teams posts a MessageCard to a Microsoft Teams incoming webhook
+type Teams struct {
+	URL    string
+	client *http.Client
+}
+
+// NewTeams creates a Microsoft Teams incoming webhook notifier
+func NewTeams(url string) *Teams {
+	return &Teams{URL: url, client: &http.Client{Timeout: webhookTimeout}}
+}
+
+// Name identifies the notifier in errors
+func (t *Teams) Name() string {
+	return "teams " + RedactURL(t.URL)
+}
+
+// Send posts the message as a MessageCard; Teams renders its text as markdown
+func (t *Teams) Send(msg *Message) error {
+	color := "2ea44f"
+	if msg.BrokenTargets > 0 {
+		color = "d73a49"
+	}
+	text := msg.Text(func(url string) string {
+		return fmt.Sprintf("[%s](%s)", url, url)
+	})
+	card := map[string]string{
+		"@type":      "MessageCard",
+		"@context":   "https://schema.org/extensions",
+		"summary":    msg.Title,
+		"title":      msg.Title,
+		"themeColor": color,
+		// Teams needs blank lines for line breaks in markdown
+		"text": strings.ReplaceAll(text, "\n", "\n\n"),
+	}
+	body, err := json.Marshal(card)
+	if err != nil {
+		return err
+	}
+	return postJSON(t.client, t.URL, body)
+}
+
+// postJSON posts a JSON body and fails on non-2xx responses
+func postJSON(client *http.Client, webhookURL string, body []byte) error {
+	req, err := http.NewRequest("POST", webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Linkchex/0.1.0 (Link Validator)")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		// The URL holds the webhook's secret, so keep it out of the error
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			return fmt.Errorf("%s %s: %w", urlErr.Op, RedactURL(urlErr.URL), urlErr.Err)
+		}
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("webhook returned %s: %s", resp.Status, strings.TrimSpace(string(detail)))
+	}
+	return nil
+}
+
+// RedactURL drops a webhook URL's path and query, which usually hold its secret
+func RedactURL(rawURL string) string {
+	if i := strings.Index(rawURL, "://"); i >= 0 {
+		if j := strings.IndexByte(rawURL[i+3:], '/'); j >= 0 {
+			return rawURL[:i+3+j] + "/..."
+		}
+	}
+	return rawURL
+}
+
+// slackEscape escapes the characters Slack treats as markup
+func slackEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
@@ -0,0 +1,215 @@
+package robots
+
+import (
+	"bufio"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Robots represents a parsed robots.txt file
+type Robots struct {
+	groups   []*group
+	Sitemaps []string // Sitemap directives, which apply regardless of user agent
+}
+
+// group is a set of rules shared by one or more user agents
+type group struct {
+	agents     []string // Lowercased user-agent tokens
+	rules      []rule
+	crawlDelay time.Duration
+}
+
+// rule is a single Allow or Disallow line
+type rule struct {
+	allow   bool
+	pattern string
+}
+
+// AllowAll returns a Robots that permits every path (used when robots.txt is missing)
+func AllowAll() *Robots {
+	return &Robots{}
+}
+
+// Parse parses robots.txt content following the grouping and matching rules
+// described in RFC 9309
+func Parse(r io.Reader) (*Robots, error) {
+	robots := &Robots{}
+	var current *group
+	lastWasAgent := false
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		// Strip comments
+		if idx := strings.Index(line, "#"); idx >= 0 {
+			line = line[:idx]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		key := strings.ToLower(strings.TrimSpace(parts[0]))
+		value := strings.TrimSpace(parts[1])
+
+		switch key {
+		case "user-agent":
+			// Consecutive user-agent lines share one group
+			if current == nil || !lastWasAgent {
+				current = &group{}
+				robots.groups = append(robots.groups, current)
+			}
+			current.agents = append(current.agents, strings.ToLower(value))
+			lastWasAgent = true
+			continue
+		case "allow", "disallow":
+			if current != nil && value != "" {
+				current.rules = append(current.rules, rule{allow: key == "allow", pattern: value})
+			}
+		case "crawl-delay":
+			if current != nil {
+				if seconds, err := strconv.ParseFloat(value, 64); err == nil && seconds > 0 {
+					current.crawlDelay = time.Duration(seconds * float64(time.Second))
+				}
+			}
+		case "sitemap":
+			robots.Sitemaps = append(robots.Sitemaps, value)
+		}
+		lastWasAgent = false
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return robots, nil
+}
+
+// Allowed reports whether the user agent may fetch the given URL
+// The longest matching rule wins; on a tie Allow beats Disallow
+func (r *Robots) Allowed(userAgent, rawURL string) bool {
+	path := "/"
+	if parsed, err := url.Parse(rawURL); err == nil {
+		path = parsed.EscapedPath()
+		if path == "" {
+			path = "/"
+		}
+		if parsed.RawQuery != "" {
+			path += "?" + parsed.RawQuery
+		}
+	}
+
+	// robots.txt itself is always fetchable
+	if path == "/robots.txt" {
+		return true
+	}
+
+	allowed := true
+	longest := -1
+	for _, g := range r.groupsFor(userAgent) {
+		for _, rl := range g.rules {
+			if !matchPattern(rl.pattern, path) {
+				continue
+			}
+			length := len(rl.pattern)
+			if length > longest || (length == longest && rl.allow) {
+				longest = length
+				allowed = rl.allow
+			}
+		}
+	}
+
+	return allowed
+}
+
+// CrawlDelay returns the Crawl-delay requested for the user agent (0 if none)
+func (r *Robots) CrawlDelay(userAgent string) time.Duration {
+	var delay time.Duration
+	for _, g := range r.groupsFor(userAgent) {
+		if g.crawlDelay > delay {
+			delay = g.crawlDelay
+		}
+	}
+	return delay
+}
+
+// groupsFor selects the groups that apply to a user agent: those naming its
+// product token (case-insensitively, per RFC 9309), falling back to the "*" groups
+func (r *Robots) groupsFor(userAgent string) []*group {
+	token := productToken(userAgent)
+
+	var matched []*group
+	for _, g := range r.groups {
+		for _, agent := range g.agents {
+			if strings.EqualFold(token, agent) {
+				matched = append(matched, g)
+				break
+			}
+		}
+	}
+	if len(matched) > 0 {
+		return matched
+	}
+
+	for _, g := range r.groups {
+		for _, agent := range g.agents {
+			if agent == "*" {
+				matched = append(matched, g)
+				break
+			}
+		}
+	}
+	return matched
+}
+
+// productToken extracts the lowercased product name from a User-Agent header
+// e.g. "Linkchex/0.1.0 (Link Validator)" -> "linkchex"
+func productToken(userAgent string) string {
+	token := strings.ToLower(strings.TrimSpace(userAgent))
+	if idx := strings.IndexAny(token, "/ "); idx >= 0 {
+		token = token[:idx]
+	}
+	return token
+}
+
+// matchPattern matches a robots.txt path pattern supporting * and a trailing $
+func matchPattern(pattern, path string) bool {
+	anchored := strings.HasSuffix(pattern, "$")
+	if anchored {
+		pattern = strings.TrimSuffix(pattern, "$")
+	}
+
+	segments := strings.Split(pattern, "*")
+
+	// The first segment must match at the start of the path
+	if !strings.HasPrefix(path, segments[0]) {
+		return false
+	}
+	pos := len(segments[0])
+
+	for i := 1; i < len(segments); i++ {
+		seg := segments[i]
+		if i == len(segments)-1 && anchored {
+			// Last segment must match at the end of the path
+			return len(path)-len(seg) >= pos && strings.HasSuffix(path, seg)
+		}
+		idx := strings.Index(path[pos:], seg)
+		if idx < 0 {
+			return false
+		}
+		pos += idx + len(seg)
+	}
+
+	if anchored {
+		return pos == len(path)
+	}
+	return true
+}
@@ -1,140 +1,254 @@
 package sitemap
 
 import (
-	"bufio"
+	"bytes"
 	"fmt"
 	"net/http"
 	"net/url"
 	"strings"
-	"time"
+	"sync"
+
+	"linkchex/internal/fetcher"
+	"linkchex/internal/robots"
+)
+
+// Probe sources
+const (
+	SourceRobots     = "robots.txt"
+	SourceHomepage   = "homepage"
+	SourceCommonPath = "common-path"
 )
 
+// commonPaths are well-known sitemap locations probed on every discovery
+var commonPaths = []string{
+	"/sitemap.xml",
+	"/sitemap_index.xml",
+	"/sitemap-index.xml",
+	"/wp-sitemap.xml",
+	"/sitemap/sitemap.xml",
+	"/sitemap/index.xml",
+	"/sitemap.xml.gz",
+	"/sitemap_index.xml.gz",
+}
+
+// Probe records a single location checked during discovery
+type Probe struct {
+	URL        string
+	Source     string // One of the Source* constants
+	StatusCode int
+	Found      []string // Sitemaps this probe yielded
+	Error      error
+}
+
+// Discovery is the outcome of sitemap discovery, including every probe made
+type Discovery struct {
+	BaseURL  string
+	Sitemaps []string
+	Probes   []Probe
+}
+
 // Discover attempts to find sitemaps from a base URL
-// It checks common locations and robots.txt
-func Discover(baseURL string) ([]string, error) {
-	// Ensure baseURL has a scheme
+// It concurrently checks robots.txt, <link rel="sitemap"> on the homepage and
+// common locations. Bare hosts are tried over https first, then http
+func Discover(client *fetcher.Client, baseURL string) (*Discovery, error) {
+	schemes := []string{""}
 	if !strings.HasPrefix(baseURL, "http://") && !strings.HasPrefix(baseURL, "https://") {
-		baseURL = "https://" + baseURL
+		schemes = []string{"https://", "http://"}
 	}
 
-	// Parse base URL
-	parsedURL, err := url.Parse(baseURL)
-	if err != nil {
-		return nil, fmt.Errorf("invalid URL: %w", err)
+	var discovery *Discovery
+	var probes []Probe
+	for _, scheme := range schemes {
+		// Parse base URL
+		parsedURL, err := url.Parse(scheme + baseURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid URL: %w", err)
+		}
+		if parsedURL.Host == "" {
+			return nil, fmt.Errorf("invalid URL: missing host in %q", baseURL)
+		}
+
+		discovery = discoverOrigin(client, fmt.Sprintf("%s://%s", parsedURL.Scheme, parsedURL.Host))
+		probes = append(probes, discovery.Probes...)
+
+		// Only fall back to http if nothing answered over https
+		if len(discovery.Sitemaps) > 0 || anyResponded(discovery.Probes) {
+			break
+		}
 	}
+	discovery.Probes = probes
 
-	baseURL = fmt.Sprintf("%s://%s", parsedURL.Scheme, parsedURL.Host)
+	if len(discovery.Sitemaps) == 0 {
+		return discovery, fmt.Errorf("no sitemap found at %s (tried %s)", discovery.BaseURL, describeProbes(probes))
+	}
 
-	var sitemaps []string
+	return discovery, nil
+}
 
-	// Check robots.txt first
-	robotsSitemaps, err := checkRobotsTxt(baseURL)
-	if err == nil && len(robotsSitemaps) > 0 {
-		sitemaps = append(sitemaps, robotsSitemaps...)
+// discoverOrigin probes all sitemap sources of a single scheme://host concurrently
+func discoverOrigin(client *fetcher.Client, origin string) *Discovery {
+	discovery := &Discovery{
+		BaseURL: origin,
+		Probes:  make([]Probe, 2+len(commonPaths)),
 	}
 
-	// If no sitemaps found in robots.txt, check common locations
-	if len(sitemaps) == 0 {
-		commonPaths := []string{
-			"/sitemap.xml",
-			"/sitemap_index.xml",
-			"/sitemap/sitemap.xml",
-			"/sitemap/index.xml",
-		}
+	var wg sync.WaitGroup
+	run := func(idx int, probe func() Probe) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			discovery.Probes[idx] = probe()
+		}()
+	}
 
-		var lastErr error
-		for _, path := range commonPaths {
-			sitemapURL := baseURL + path
-			exists, err := urlExists(sitemapURL)
-			if err != nil {
-				lastErr = err
-				continue
-			}
-			if exists {
-				sitemaps = append(sitemaps, sitemapURL)
-				break // Found one, stop searching
-			}
-		}
+	run(0, func() Probe { return checkRobotsTxt(client, origin) })
+	run(1, func() Probe { return checkHomepage(client, origin) })
+	for i, path := range commonPaths {
+		sitemapURL := origin + path
+		run(2+i, func() Probe { return checkCommonPath(client, sitemapURL) })
+	}
 
-		if len(sitemaps) == 0 && lastErr != nil {
-			return nil, fmt.Errorf("no sitemap found at %s (last error: %v)", baseURL, lastErr)
+	wg.Wait()
+
+	// Declared sitemaps (robots.txt, homepage) take precedence over guessed paths
+	seen := make(map[string]bool)
+	for _, probe := range discovery.Probes {
+		if probe.Source == SourceCommonPath {
+			continue
+		}
+		for _, sitemapURL := range probe.Found {
+			if !seen[sitemapURL] {
+				seen[sitemapURL] = true
+				discovery.Sitemaps = append(discovery.Sitemaps, sitemapURL)
+			}
 		}
 	}
 
-	if len(sitemaps) == 0 {
-		return nil, fmt.Errorf("no sitemap found at %s", baseURL)
+	// Common paths are often aliases of one another, or an index and its own
+	// child, so only the first that answers is used
+	if len(discovery.Sitemaps) == 0 {
+		for _, probe := range discovery.Probes {
+			if probe.Source == SourceCommonPath && len(probe.Found) > 0 {
+				discovery.Sitemaps = probe.Found
+				break
+			}
+		}
 	}
 
-	return sitemaps, nil
+	return discovery
 }
 
 // checkRobotsTxt parses robots.txt and extracts Sitemap directives
-func checkRobotsTxt(baseURL string) ([]string, error) {
-	robotsURL := baseURL + "/robots.txt"
+func checkRobotsTxt(client *fetcher.Client, origin string) Probe {
+	probe := Probe{URL: origin + "/robots.txt", Source: SourceRobots}
+
+	resp := client.Get(probe.URL)
+	probe.StatusCode = resp.StatusCode
+	if resp.Error != nil {
+		probe.Error = resp.Error
+		return probe
+	}
 
-	client := &http.Client{
-		Timeout: 10 * time.Second,
+	if resp.StatusCode != http.StatusOK {
+		probe.Error = fmt.Errorf("robots.txt returned status %d", resp.StatusCode)
+		return probe
 	}
 
-	resp, err := client.Get(robotsURL)
+	parsed, err := robots.Parse(bytes.NewReader(resp.Body))
 	if err != nil {
-		return nil, err
+		probe.Error = err
+		return probe
+	}
+
+	probe.Found = parsed.Sitemaps
+	return probe
+}
+
+// checkHomepage looks for <link rel="sitemap" href="..."> on the homepage
+func checkHomepage(client *fetcher.Client, origin string) Probe {
+	probe := Probe{URL: origin + "/", Source: SourceHomepage}
+
+	resp := client.Get(probe.URL)
+	probe.StatusCode = resp.StatusCode
+	if resp.Error != nil {
+		probe.Error = resp.Error
+		return probe
 	}
-	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("robots.txt returned status %d", resp.StatusCode)
+		probe.Error = fmt.Errorf("homepage returned status %d", resp.StatusCode)
+		return probe
 	}
 
-	var sitemaps []string
-	scanner := bufio.NewScanner(resp.Body)
+	links, err := fetcher.ExtractLinks(resp.Body, resp.FinalURL, false)
+	if err != nil {
+		probe.Error = err
+		return probe
+	}
 
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-		if strings.HasPrefix(strings.ToLower(line), "sitemap:") {
-			// Extract sitemap URL
-			parts := strings.SplitN(line, ":", 2)
-			if len(parts) == 2 {
-				sitemapURL := strings.TrimSpace(parts[1])
-				sitemaps = append(sitemaps, sitemapURL)
-			}
+	for _, link := range links {
+		if link.Tag == "link" && link.HasRel("sitemap") {
+			probe.Found = append(probe.Found, link.URL)
 		}
 	}
+	return probe
+}
 
-	if err := scanner.Err(); err != nil {
-		return nil, err
-	}
+// checkCommonPath checks whether a well-known sitemap location exists
+func checkCommonPath(client *fetcher.Client, sitemapURL string) Probe {
+	probe := Probe{URL: sitemapURL, Source: SourceCommonPath}
 
-	return sitemaps, nil
-}
+	// Use HEAD request first (faster)
+	resp := client.Head(sitemapURL)
 
-// urlExists checks if a URL returns a successful status code
-func urlExists(urlStr string) (bool, error) {
-	client := &http.Client{
-		Timeout: 10 * time.Second,
+	// Some servers don't support HEAD, fallback to GET if needed
+	if resp.Error == nil && (resp.StatusCode == http.StatusMethodNotAllowed || resp.StatusCode == http.StatusNotImplemented) {
+		resp = client.Get(sitemapURL)
 	}
 
-	// Use HEAD request first (faster)
-	resp, err := client.Head(urlStr)
-	if err != nil {
-		return false, err
+	probe.StatusCode = resp.StatusCode
+	if resp.Error != nil {
+		probe.Error = resp.Error
+		return probe
 	}
-	defer resp.Body.Close()
 
-	// Some servers don't support HEAD, fallback to GET if needed
-	if resp.StatusCode == http.StatusMethodNotAllowed {
-		resp, err = client.Get(urlStr)
-		if err != nil {
-			return false, err
+	// Report rate limiting so the caller knows what happened
+	if resp.StatusCode == http.StatusTooManyRequests {
+		probe.Error = fmt.Errorf("rate limited (429)")
+		return probe
+	}
+
+	// Accept 2xx status codes as success, unless a catch-all route served an HTML page
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		if strings.HasPrefix(strings.ToLower(resp.Header.Get("Content-Type")), "text/html") {
+			probe.Error = fmt.Errorf("served an HTML page, not a sitemap")
+			return probe
 		}
-		defer resp.Body.Close()
+		probe.Found = []string{sitemapURL}
 	}
 
-	// Return error for rate limiting so caller knows what happened
-	if resp.StatusCode == http.StatusTooManyRequests {
-		return false, fmt.Errorf("rate limited (429)")
+	return probe
+}
+
+// anyResponded reports whether any probe got an HTTP response
+func anyResponded(probes []Probe) bool {
+	for _, probe := range probes {
+		if probe.StatusCode > 0 {
+			return true
+		}
 	}
+	return false
+}
 
-	// Accept 2xx status codes as success
-	return resp.StatusCode >= 200 && resp.StatusCode < 300, nil
+// describeProbes summarizes probes for error messages
+func describeProbes(probes []Probe) string {
+	parts := make([]string, 0, len(probes))
+	for _, probe := range probes {
+		outcome := fmt.Sprintf("%d", probe.StatusCode)
+		if probe.Error != nil && probe.StatusCode == 0 {
+			outcome = "error"
+		}
+		parts = append(parts, fmt.Sprintf("%s [%s]", probe.URL, outcome))
+	}
+	return strings.Join(parts, ", ")
 }
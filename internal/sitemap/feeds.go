@@ -0,0 +1,96 @@
+package sitemap
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// RSSItem represents an <item> in an RSS 2.0 or RSS 1.0 (RDF) feed
+type RSSItem struct {
+	Links []string `xml:"link"`
+	GUID  string   `xml:"guid"`
+}
+
+// AtomEntry represents an <entry> in an Atom feed
+type AtomEntry struct {
+	Links []AtomLink `xml:"link"`
+}
+
+// AtomLink represents an Atom <link> element
+type AtomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+}
+
+// pageURL returns the item's page URL, preferring <link> over a permalink <guid>
+func (item RSSItem) pageURL() string {
+	for _, link := range item.Links {
+		if link = strings.TrimSpace(link); link != "" {
+			return link
+		}
+	}
+	if isHTTPURL(item.GUID) {
+		return strings.TrimSpace(item.GUID)
+	}
+	return ""
+}
+
+// pageURL returns the entry's alternate link (a link without rel defaults to alternate)
+func (entry AtomEntry) pageURL() string {
+	for _, link := range entry.Links {
+		if link.Href != "" && (link.Rel == "" || link.Rel == "alternate") {
+			return strings.TrimSpace(link.Href)
+		}
+	}
+	return ""
+}
+
+// ReadURLList reads a plain-text list of URLs, one per line
+// Blank lines, # comments and non-HTTP lines are ignored
+func ReadURLList(r io.Reader) ([]string, error) {
+	var urls []string
+	err := decodePlainText(r, func(u URL) {
+		urls = append(urls, u.Loc)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return urls, nil
+}
+
+// decodePlainText parses a text sitemap as described by the sitemaps protocol
+func decodePlainText(r io.Reader, onURL func(URL)) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(strings.TrimPrefix(scanner.Text(), "\ufeff"))
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if !isHTTPURL(line) {
+			continue
+		}
+		onURL(URL{Loc: line})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read URL list: %w", err)
+	}
+	return nil
+}
+
+// looksLikeXML reports whether content starts with markup after any BOM/whitespace
+func looksLikeXML(r *bufio.Reader) bool {
+	peek, _ := r.Peek(512)
+	trimmed := strings.TrimLeft(strings.TrimPrefix(string(peek), "\ufeff"), " \t\r\n")
+	return strings.HasPrefix(trimmed, "<")
+}
+
+// isHTTPURL reports whether s is an absolute http(s) URL
+func isHTTPURL(s string) bool {
+	s = strings.TrimSpace(s)
+	return strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://")
+}
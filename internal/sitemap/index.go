@@ -0,0 +1,203 @@
+package sitemap
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"linkchex/internal/fetcher"
+)
+
+// Defaults for sitemap index recursion
+const (
+	DefaultMaxDepth    = 5
+	DefaultConcurrency = 10
+)
+
+// Issue kinds reported while walking a sitemap tree
+const (
+	IssueFetch     = "fetch-failed" // Child sitemap could not be fetched or parsed
+	IssueCycle     = "cycle"        // Child references one of its ancestors
+	IssueDuplicate = "duplicate"    // Child was already referenced elsewhere in the tree
+	IssueMaxDepth  = "max-depth"    // Index nesting exceeded Options.MaxDepth
+	IssueLimit     = "limit"        // Protocol size or entry limit exceeded
+)
+
+// Options controls how sitemap index files are followed
+type Options struct {
+	MaxDepth    int             // Maximum index nesting below the root (0 = DefaultMaxDepth)
+	Concurrency int             // Sitemaps fetched in parallel (0 = DefaultConcurrency)
+	Client      *fetcher.Client // Client for remote sitemaps (nil = a default client)
+}
+
+// Issue is a problem with a sitemap in the tree that didn't stop parsing
+type Issue struct {
+	Sitemap string
+	Parent  string // The index that referenced Sitemap ("" for the root)
+	Depth   int
+	Kind    string // One of the Issue* constants
+	Error   string
+}
+
+// String formats an issue for logs
+func (i Issue) String() string {
+	if i.Parent != "" {
+		return fmt.Sprintf("%s: sitemap %s (from %s): %s", i.Kind, i.Sitemap, i.Parent, i.Error)
+	}
+	return fmt.Sprintf("%s: sitemap %s: %s", i.Kind, i.Sitemap, i.Error)
+}
+
+// Result holds the URLs of a sitemap tree and any issues met along the way
+type Result struct {
+	URLs   []URL
+	Issues []Issue
+}
+
+// ParseTree parses a sitemap and, for index files, its children concurrently,
+// guarding against cycles and excessive nesting
+// Only a failure of the root sitemap is returned as an error
+func ParseTree(sitemapURL string, opts Options) (*Result, error) {
+	if opts.MaxDepth <= 0 {
+		opts.MaxDepth = DefaultMaxDepth
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = DefaultConcurrency
+	}
+	if opts.Client == nil {
+		opts.Client = fetcher.NewClient(30, 0)
+	}
+
+	w := &treeWalker{
+		opts:      opts,
+		visited:   map[string]bool{sitemapURL: true},
+		semaphore: make(chan struct{}, opts.Concurrency),
+	}
+
+	urls, err := w.walk(sitemapURL, "", 0, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Issues arrive in completion order; sort them for stable output
+	sort.SliceStable(w.issues, func(i, j int) bool {
+		if w.issues[i].Depth != w.issues[j].Depth {
+			return w.issues[i].Depth < w.issues[j].Depth
+		}
+		return w.issues[i].Sitemap < w.issues[j].Sitemap
+	})
+
+	return &Result{URLs: urls, Issues: w.issues}, nil
+}
+
+// treeWalker holds the shared state of a single ParseTree call
+type treeWalker struct {
+	opts      Options
+	visited   map[string]bool
+	issues    []Issue
+	semaphore chan struct{}
+	mu        sync.Mutex
+}
+
+// walk parses one sitemap and recurses into its children
+func (w *treeWalker) walk(sitemapURL, parent string, depth int, ancestors []string) ([]URL, error) {
+	// Hold a slot only while fetching, never while waiting on children
+	w.semaphore <- struct{}{}
+	urls, children, issues, err := parseDocument(w.opts.Client, sitemapURL)
+	<-w.semaphore
+
+	if err != nil {
+		return nil, err
+	}
+	for _, issue := range issues {
+		issue.Parent = parent
+		issue.Depth = depth
+		w.addIssue(issue)
+	}
+
+	if len(children) == 0 {
+		return urls, nil
+	}
+
+	if depth >= w.opts.MaxDepth {
+		w.addIssue(Issue{
+			Sitemap: sitemapURL,
+			Parent:  parent,
+			Depth:   depth,
+			Kind:    IssueMaxDepth,
+			Error:   fmt.Sprintf("not following %d child sitemap(s) beyond depth %d", len(children), w.opts.MaxDepth),
+		})
+		return urls, nil
+	}
+
+	path := append(append([]string(nil), ancestors...), sitemapURL)
+	childURLs := make([][]URL, len(children))
+	var wg sync.WaitGroup
+
+	for i, child := range children {
+		issue := Issue{Sitemap: child.Loc, Parent: sitemapURL, Depth: depth + 1}
+
+		if contains(path, child.Loc) {
+			issue.Kind = IssueCycle
+			issue.Error = "index references one of its ancestors"
+			w.addIssue(issue)
+			continue
+		}
+		if !w.markVisited(child.Loc) {
+			issue.Kind = IssueDuplicate
+			issue.Error = "already referenced by another index"
+			w.addIssue(issue)
+			continue
+		}
+
+		wg.Add(1)
+		go func(idx int, issue Issue) {
+			defer wg.Done()
+
+			result, err := w.walk(issue.Sitemap, sitemapURL, depth+1, path)
+			if err != nil {
+				issue.Kind = IssueFetch
+				issue.Error = err.Error()
+				w.addIssue(issue)
+				return
+			}
+			childURLs[idx] = result
+		}(i, issue)
+	}
+
+	wg.Wait()
+
+	// Concatenate in index order so output is deterministic
+	for _, result := range childURLs {
+		urls = append(urls, result...)
+	}
+	return urls, nil
+}
+
+// markVisited records a sitemap, reporting false if it was already seen
+func (w *treeWalker) markVisited(sitemapURL string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.visited[sitemapURL] {
+		return false
+	}
+	w.visited[sitemapURL] = true
+	return true
+}
+
+// addIssue records a non-fatal issue
+func (w *treeWalker) addIssue(issue Issue) {
+	w.mu.Lock()
+	w.issues = append(w.issues, issue)
+	w.mu.Unlock()
+}
+
+// contains reports whether a slice contains a value
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -1,13 +1,19 @@
 package sitemap
 
 import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"strings"
 	"time"
+
+	"linkchex/internal/fetcher"
 )
 
 // URLSet represents the root element of a sitemap
@@ -18,10 +24,34 @@ type URLSet struct {
 
 // URL represents a single URL entry in a sitemap
 type URL struct {
-	Loc        string `xml:"loc"`
-	LastMod    string `xml:"lastmod"`
-	ChangeFreq string `xml:"changefreq"`
-	Priority   string `xml:"priority"`
+	Loc        string      `xml:"loc"`
+	LastMod    string      `xml:"lastmod"`
+	ChangeFreq string      `xml:"changefreq"`
+	Priority   string      `xml:"priority"`
+	Alternates []Alternate `xml:"link"`  // xhtml:link hreflang alternates
+	Images     []Image     `xml:"image"` // Google image sitemap extension
+	Videos     []Video     `xml:"video"` // Google video sitemap extension
+	Sitemap    string      `xml:"-"`     // The sitemap file this entry was read from
+}
+
+// Image represents an <image:image> entry
+type Image struct {
+	Loc string `xml:"loc"`
+}
+
+// Video represents a <video:video> entry
+type Video struct {
+	ContentLoc   string `xml:"content_loc"`
+	PlayerLoc    string `xml:"player_loc"`
+	ThumbnailLoc string `xml:"thumbnail_loc"`
+	Title        string `xml:"title"`
+}
+
+// Alternate represents an <xhtml:link rel="alternate" hreflang="..."> entry
+type Alternate struct {
+	Rel      string `xml:"rel,attr"`
+	Hreflang string `xml:"hreflang,attr"`
+	Href     string `xml:"href,attr"`
 }
 
 // SitemapIndex represents a sitemap index file
@@ -36,88 +66,223 @@ type Sitemap struct {
 	LastMod string `xml:"lastmod"`
 }
 
+// Limits from the sitemaps protocol (https://www.sitemaps.org/protocol.html)
+const (
+	MaxSitemapURLs  = 50000
+	MaxSitemapBytes = 50 * 1024 * 1024 // Uncompressed size
+)
+
 // Parse parses a sitemap URL or local file and returns all URLs
-// Handles both regular sitemaps and sitemap index files
+// Handles XML sitemaps, sitemap index files, plain-text sitemaps and
+// RSS/Atom feeds, plain or gzip-compressed
 func Parse(sitemapURL string) ([]string, error) {
-	var reader io.ReadCloser
-	var err error
+	entries, err := ParseURLs(sitemapURL)
+	if err != nil {
+		return nil, err
+	}
 
-	// Check if it's a local file or remote URL
-	if strings.HasPrefix(sitemapURL, "http://") || strings.HasPrefix(sitemapURL, "https://") {
-		reader, err = fetchRemoteSitemap(sitemapURL)
-	} else {
-		reader, err = openLocalSitemap(sitemapURL)
+	urls := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		urls = append(urls, entry.Loc)
 	}
+	return urls, nil
+}
 
+// ParseURLs is like Parse but keeps each entry's metadata (lastmod,
+// changefreq, priority) and the sitemap it came from
+// Problems with child sitemaps are printed to stderr; use ParseTree to
+// receive them as structured issues instead
+func ParseURLs(sitemapURL string) ([]URL, error) {
+	result, err := ParseTree(sitemapURL, Options{})
 	if err != nil {
 		return nil, err
 	}
-	defer reader.Close()
 
-	// Read all content
-	content, err := io.ReadAll(reader)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read sitemap: %w", err)
+	for _, issue := range result.Issues {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", issue)
 	}
+	return result.URLs, nil
+}
 
-	// Try parsing as sitemap index first
-	var sitemapIndex SitemapIndex
-	if err := xml.Unmarshal(content, &sitemapIndex); err == nil && len(sitemapIndex.Sitemaps) > 0 {
-		// It's a sitemap index, recursively parse each sitemap
-		return parseSitemapIndex(&sitemapIndex)
+// parseDocument reads a single sitemap document without following index entries
+func parseDocument(client *fetcher.Client, sitemapURL string) ([]URL, []Sitemap, []Issue, error) {
+	reader, err := openSitemap(client, sitemapURL)
+	if err != nil {
+		return nil, nil, nil, err
 	}
+	defer reader.Close()
+
+	counter := &countingReader{r: reader}
+	buffered := bufio.NewReader(counter)
 
-	// Parse as regular sitemap
-	var urlSet URLSet
-	if err := xml.Unmarshal(content, &urlSet); err != nil {
-		return nil, fmt.Errorf("failed to parse sitemap XML: %w", err)
+	var urls []URL
+	var children []Sitemap
+	onURL := func(u URL) {
+		if u.Loc = strings.TrimSpace(u.Loc); u.Loc != "" {
+			u.Sitemap = sitemapURL
+			urls = append(urls, u)
+		}
 	}
 
-	// Extract URLs
-	var urls []string
-	for _, url := range urlSet.URLs {
-		if url.Loc != "" {
-			urls = append(urls, url.Loc)
+	if looksLikeXML(buffered) {
+		err = decodeSitemap(buffered, onURL, func(sm Sitemap) {
+			if sm.Loc = strings.TrimSpace(sm.Loc); sm.Loc != "" {
+				children = append(children, sm)
+			}
+		})
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to parse sitemap XML: %w", err)
 		}
+	} else if err := decodePlainText(buffered, onURL); err != nil {
+		return nil, nil, nil, err
 	}
 
-	return urls, nil
+	// Protocol limits are advisory, so only warn
+	var issues []Issue
+	if counter.n > MaxSitemapBytes {
+		issues = append(issues, Issue{
+			Sitemap: sitemapURL,
+			Kind:    IssueLimit,
+			Error:   fmt.Sprintf("%d bytes uncompressed (limit %d)", counter.n, MaxSitemapBytes),
+		})
+	}
+	if count := len(urls) + len(children); count > MaxSitemapURLs {
+		issues = append(issues, Issue{
+			Sitemap: sitemapURL,
+			Kind:    IssueLimit,
+			Error:   fmt.Sprintf("%d entries (limit %d)", count, MaxSitemapURLs),
+		})
+	}
+
+	return urls, children, issues, nil
 }
 
-// parseSitemapIndex recursively parses a sitemap index file
-func parseSitemapIndex(index *SitemapIndex) ([]string, error) {
-	var allURLs []string
+// decodeSitemap stream-decodes a <urlset>, <sitemapindex>, RSS or Atom document,
+// invoking the callbacks for each entry so memory doesn't grow with file size
+func decodeSitemap(r io.Reader, onURL func(URL), onSitemap func(Sitemap)) error {
+	decoder := xml.NewDecoder(r)
+	decoder.Strict = false
+	sawRoot := false
 
-	for _, sitemap := range index.Sitemaps {
-		urls, err := Parse(sitemap.Loc)
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
 		if err != nil {
-			// Log error but continue with other sitemaps
-			fmt.Fprintf(os.Stderr, "Warning: Failed to parse sitemap %s: %v\n", sitemap.Loc, err)
+			return err
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok {
 			continue
 		}
-		allURLs = append(allURLs, urls...)
+
+		switch start.Name.Local {
+		case "urlset", "sitemapindex", "rss", "RDF", "feed":
+			sawRoot = true
+		case "url":
+			var u URL
+			if err := decoder.DecodeElement(&u, &start); err != nil {
+				return err
+			}
+			onURL(u)
+		case "sitemap":
+			var sm Sitemap
+			if err := decoder.DecodeElement(&sm, &start); err != nil {
+				return err
+			}
+			onSitemap(sm)
+		case "item":
+			var item RSSItem
+			if err := decoder.DecodeElement(&item, &start); err != nil {
+				return err
+			}
+			onURL(URL{Loc: item.pageURL()})
+		case "entry":
+			var entry AtomEntry
+			if err := decoder.DecodeElement(&entry, &start); err != nil {
+				return err
+			}
+			onURL(URL{Loc: entry.pageURL()})
+		}
 	}
 
-	return allURLs, nil
+	if !sawRoot {
+		return fmt.Errorf("no <urlset>, <sitemapindex>, <rss> or <feed> root element")
+	}
+	return nil
 }
 
-// fetchRemoteSitemap fetches a sitemap from a remote URL
-func fetchRemoteSitemap(url string) (io.ReadCloser, error) {
-	client := &http.Client{
-		Timeout: 30 * time.Second,
+// lastModLayouts are the W3C Datetime forms allowed for <lastmod>
+var lastModLayouts = []string{
+	time.RFC3339Nano,
+	time.RFC3339,
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02",
+	"2006-01",
+	"2006",
+}
+
+// ParseLastMod parses a <lastmod> value in any W3C Datetime form
+func ParseLastMod(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range lastModLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
 	}
+	return time.Time{}, fmt.Errorf("invalid lastmod %q", value)
+}
 
-	resp, err := client.Get(url)
+// openSitemap opens a local or remote sitemap, transparently decompressing gzip
+func openSitemap(client *fetcher.Client, sitemapURL string) (io.ReadCloser, error) {
+	var reader io.ReadCloser
+	var err error
+
+	// Check if it's a local file or remote URL
+	if strings.HasPrefix(sitemapURL, "http://") || strings.HasPrefix(sitemapURL, "https://") {
+		reader, err = fetchRemoteSitemap(client, sitemapURL)
+	} else {
+		reader, err = openLocalSitemap(sitemapURL)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch sitemap: %w", err)
+		return nil, err
+	}
+
+	// Only the gzip magic bytes decide: a .gz extension or gzip Content-Type
+	// can't be trusted, since the HTTP transport may have already decompressed
+	// a response sent with Content-Encoding: gzip
+	buffered := bufio.NewReader(reader)
+	if magic, err := buffered.Peek(2); err != nil || magic[0] != 0x1f || magic[1] != 0x8b {
+		return readCloser{Reader: buffered, Closer: reader}, nil
+	}
+
+	gz, err := gzip.NewReader(buffered)
+	if err != nil {
+		reader.Close()
+		return nil, fmt.Errorf("failed to decompress sitemap: %w", err)
+	}
+	return readCloser{Reader: gz, Closer: reader}, nil
+}
+
+// fetchRemoteSitemap fetches a sitemap from a remote URL through the shared
+// client, so its user agent, rate limits and robots.txt rules apply
+func fetchRemoteSitemap(client *fetcher.Client, url string) (io.ReadCloser, error) {
+	if !client.Allowed(url) {
+		return nil, errors.New("sitemap disallowed by robots.txt")
+	}
+
+	resp := client.Get(url)
+	if resp.Error != nil {
+		return nil, fmt.Errorf("failed to fetch sitemap: %w", resp.Error)
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		resp.Body.Close()
 		return nil, fmt.Errorf("sitemap returned status %d", resp.StatusCode)
 	}
 
-	return resp.Body, nil
+	return io.NopCloser(bytes.NewReader(resp.Body)), nil
 }
 
 // openLocalSitemap opens a local sitemap file
@@ -128,3 +293,21 @@ func openLocalSitemap(path string) (io.ReadCloser, error) {
 	}
 	return file, nil
 }
+
+// readCloser pairs a (possibly wrapped) reader with the underlying closer
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// countingReader counts bytes read through it
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
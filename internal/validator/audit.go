@@ -0,0 +1,197 @@
+package validator
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"linkchex/internal/sitemap"
+)
+
+// Sitemap audit issue types
+const (
+	IssueNonOK             = "non-200"
+	IssueRedirect          = "redirect"
+	IssueRobotsBlocked     = "robots-blocked"
+	IssueNoIndex           = "noindex"
+	IssueCanonicalMismatch = "canonical-mismatch"
+	IssueDuplicate         = "duplicate"
+	IssueOffHost           = "off-host"
+	IssueInvalidLastMod    = "invalid-lastmod"
+	IssueFutureLastMod     = "future-lastmod"
+	IssueInvalidChangeFreq = "invalid-changefreq"
+	IssueInvalidPriority   = "invalid-priority"
+)
+
+// validChangeFreqs are the <changefreq> values allowed by the sitemaps protocol
+var validChangeFreqs = map[string]bool{
+	"always": true, "hourly": true, "daily": true, "weekly": true,
+	"monthly": true, "yearly": true, "never": true,
+}
+
+// SitemapFinding is a single problem found with a sitemap entry
+type SitemapFinding struct {
+	URL     string
+	Sitemap string // The sitemap file that listed the URL
+	Issue   string // One of the Issue* constants
+	Detail  string
+}
+
+// SitemapAudit summarizes the health of the sitemap(s) used as input
+type SitemapAudit struct {
+	URLsAudited  int
+	Findings     []SitemapFinding
+	IssuesByType map[string]int
+}
+
+// AuditSitemap checks sitemap entries against the crawl results in report and
+// attaches the findings as report.SitemapAudit
+func (v *Validator) AuditSitemap(entries []sitemap.URL, report *ValidationReport) *SitemapAudit {
+	audit := &SitemapAudit{
+		URLsAudited:  len(entries),
+		IssuesByType: make(map[string]int),
+	}
+
+	add := func(entry sitemap.URL, issue, detail string) {
+		audit.Findings = append(audit.Findings, SitemapFinding{
+			URL:     entry.Loc,
+			Sitemap: entry.Sitemap,
+			Issue:   issue,
+			Detail:  detail,
+		})
+		audit.IssuesByType[issue]++
+	}
+
+	pages := make(map[string]PageInfo, len(report.Pages))
+	for _, page := range report.Pages {
+		pages[page.URL] = page
+	}
+
+	expectedHost := expectedSitemapHost(entries)
+	now := time.Now()
+
+	// Index every sitemap each URL appears in to find duplicates
+	seenIn := make(map[string][]string)
+	for _, entry := range entries {
+		seenIn[entry.Loc] = append(seenIn[entry.Loc], entry.Sitemap)
+	}
+	audited := make(map[string]bool)
+
+	for _, entry := range entries {
+		// Entry metadata is checked per occurrence, the page itself only once
+		firstOccurrence := !audited[entry.Loc]
+		audited[entry.Loc] = true
+
+		if sitemaps := seenIn[entry.Loc]; len(sitemaps) > 1 && firstOccurrence {
+			add(entry, IssueDuplicate, fmt.Sprintf("listed %d times in: %s", len(sitemaps), strings.Join(uniqueStrings(sitemaps), ", ")))
+		}
+
+		if parsed, err := url.Parse(entry.Loc); err == nil && expectedHost != "" && !strings.EqualFold(parsed.Host, expectedHost) {
+			add(entry, IssueOffHost, fmt.Sprintf("host %s differs from sitemap host %s", parsed.Host, expectedHost))
+		}
+
+		if entry.LastMod != "" {
+			if lastMod, err := sitemap.ParseLastMod(entry.LastMod); err != nil {
+				add(entry, IssueInvalidLastMod, fmt.Sprintf("lastmod %q is not a W3C Datetime", entry.LastMod))
+			} else if lastMod.After(now) {
+				add(entry, IssueFutureLastMod, fmt.Sprintf("lastmod %s is in the future", entry.LastMod))
+			}
+		}
+
+		if entry.ChangeFreq != "" && !validChangeFreqs[strings.ToLower(strings.TrimSpace(entry.ChangeFreq))] {
+			add(entry, IssueInvalidChangeFreq, fmt.Sprintf("changefreq %q is not a valid value", entry.ChangeFreq))
+		}
+
+		if entry.Priority != "" {
+			if priority, err := strconv.ParseFloat(strings.TrimSpace(entry.Priority), 64); err != nil || priority < 0 || priority > 1 {
+				add(entry, IssueInvalidPriority, fmt.Sprintf("priority %q is not between 0.0 and 1.0", entry.Priority))
+			}
+		}
+
+		if !firstOccurrence {
+			continue
+		}
+
+		page, crawled := pages[entry.Loc]
+
+		// Blocked pages were never fetched, so check robots.txt directly
+		if page.Blocked || !v.client.RobotsAllows(entry.Loc) {
+			add(entry, IssueRobotsBlocked, "disallowed by robots.txt")
+			continue
+		}
+
+		if !crawled {
+			continue
+		}
+
+		if page.StatusCode != 200 {
+			detail := fmt.Sprintf("returned status %d", page.StatusCode)
+			if page.StatusCode == 0 && page.Error != nil {
+				detail = page.Error.Error()
+			}
+			add(entry, IssueNonOK, detail)
+		}
+
+		if page.FinalURL != "" && page.FinalURL != entry.Loc {
+			add(entry, IssueRedirect, fmt.Sprintf("redirects to %s", page.FinalURL))
+		}
+
+		if page.NoIndex {
+			add(entry, IssueNoIndex, "page is marked noindex")
+		}
+
+		if page.Canonical != "" && stripFragment(page.Canonical) != stripFragment(entry.Loc) {
+			add(entry, IssueCanonicalMismatch, fmt.Sprintf("canonical points to %s", page.Canonical))
+		}
+	}
+
+	sort.SliceStable(audit.Findings, func(i, j int) bool {
+		return audit.Findings[i].Issue < audit.Findings[j].Issue
+	})
+
+	report.SitemapAudit = audit
+	return audit
+}
+
+// expectedSitemapHost returns the host sitemap URLs must share: the host of
+// the first remote sitemap, or for local files the most common entry host
+func expectedSitemapHost(entries []sitemap.URL) string {
+	counts := make(map[string]int)
+	best := ""
+	for _, entry := range entries {
+		if parsed, err := url.Parse(entry.Sitemap); err == nil && parsed.Host != "" {
+			return parsed.Host
+		}
+		if parsed, err := url.Parse(entry.Loc); err == nil && parsed.Host != "" {
+			counts[parsed.Host]++
+			if counts[parsed.Host] > counts[best] {
+				best = parsed.Host
+			}
+		}
+	}
+	return best
+}
+
+// stripFragment removes a #fragment from a URL
+func stripFragment(s string) string {
+	if idx := strings.Index(s, "#"); idx >= 0 {
+		return s[:idx]
+	}
+	return s
+}
+
+// uniqueStrings returns the distinct values of a slice, preserving order
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool)
+	var unique []string
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,134 @@
+package validator
+
+import (
+	"sort"
+
+	"linkchex/internal/fetcher"
+)
+
+// LinkOccurrence is one place a link appears
+type LinkOccurrence struct {
+	SourceURL string
+	URL       string // As written on the page, before canonicalization
+	Tag       string
+	LinkText  string
+}
+
+// BrokenTarget is a broken URL with every place it is linked from
+type BrokenTarget struct {
+	URL         string // First form the URL was seen in
+	Canonical   string `json:"-"` // What occurrences are grouped by
+	StatusCode  int
+	Status      string
+	Error       string `json:",omitempty"`
+	Pages       int    // Distinct source pages
+	Occurrences []LinkOccurrence
+}
+
+// recordDuplicates remembers links that FilterLinks drops because the same
+// canonical URL already appears on the page, so grouped reports can list
+// every occurrence even though each URL is validated once per page
+func (v *Validator) recordDuplicates(pageURL string, all, kept []fetcher.Link) {
+	if len(all) == len(kept) {
+		return
+	}
+
+	validated := make(map[string]bool, len(kept))
+	for _, link := range kept {
+		validated[v.canon.Canonical(link.URL)] = true
+	}
+
+	seen := make(map[string]bool, len(kept))
+	var extras []fetcher.Link
+	for _, link := range all {
+		key := v.canon.Canonical(link.URL)
+		if !validated[key] {
+			continue
+		}
+		if seen[key] {
+			extras = append(extras, link)
+		}
+		seen[key] = true
+	}
+	if len(extras) == 0 {
+		return
+	}
+
+	v.duplicatesMutex.Lock()
+	defer v.duplicatesMutex.Unlock()
+	for _, link := range extras {
+		key := v.canon.Canonical(link.URL)
+		v.duplicates[key] = append(v.duplicates[key], LinkOccurrence{
+			SourceURL: pageURL,
+			URL:       link.URL,
+			Tag:       link.Tag,
+			LinkText:  link.Text,
+		})
+	}
+}
+
+// groupBrokenTargets groups broken results by canonical target URL, most
+// widespread first
+func (v *Validator) groupBrokenTargets(results []Result) []BrokenTarget {
+	groups := make(map[string]*BrokenTarget)
+	var order []string
+
+	for _, result := range results {
+		if !result.IsBroken {
+			continue
+		}
+
+		key := v.canon.Canonical(result.TargetURL)
+		group, ok := groups[key]
+		if !ok {
+			group = &BrokenTarget{
+				URL:        result.TargetURL,
+				Canonical:  key,
+				StatusCode: result.StatusCode,
+				Status:     result.Status,
+			}
+			if result.Error != nil {
+				group.Error = result.Error.Error()
+			}
+			groups[key] = group
+			order = append(order, key)
+		}
+		group.Occurrences = append(group.Occurrences, LinkOccurrence{
+			SourceURL: result.SourceURL,
+			URL:       result.TargetURL,
+			Tag:       result.Tag,
+			LinkText:  result.LinkText,
+		})
+	}
+
+	v.duplicatesMutex.Lock()
+	for _, key := range order {
+		groups[key].Occurrences = append(groups[key].Occurrences, v.duplicates[key]...)
+	}
+	v.duplicatesMutex.Unlock()
+
+	targets := make([]BrokenTarget, 0, len(order))
+	for _, key := range order {
+		group := groups[key]
+		sort.SliceStable(group.Occurrences, func(i, j int) bool {
+			return group.Occurrences[i].SourceURL < group.Occurrences[j].SourceURL
+		})
+
+		pages := make(map[string]bool)
+		for _, occurrence := range group.Occurrences {
+			pages[occurrence.SourceURL] = true
+		}
+		group.Pages = len(pages)
+
+		targets = append(targets, *group)
+	}
+
+	sort.SliceStable(targets, func(i, j int) bool {
+		if len(targets[i].Occurrences) != len(targets[j].Occurrences) {
+			return len(targets[i].Occurrences) > len(targets[j].Occurrences)
+		}
+		return targets[i].URL < targets[j].URL
+	})
+
+	return targets
+}
@@ -0,0 +1,117 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+
+	"linkchex/internal/fetcher"
+)
+
+// Asset kinds used for content-type and size checks
+const (
+	AssetImage      = "image"
+	AssetScript     = "script"
+	AssetStylesheet = "stylesheet"
+)
+
+// javascriptTypes are the MIME types browsers accept for scripts
+var javascriptTypes = map[string]bool{
+	"application/javascript":   true,
+	"text/javascript":          true,
+	"application/x-javascript": true,
+	"application/ecmascript":   true,
+	"text/ecmascript":          true,
+}
+
+// contentChecks configures content-type and size assertions for linked assets
+type contentChecks struct {
+	client     *fetcher.Client
+	checkTypes bool
+	maxSizes   map[string]int64 // Maximum bytes by asset kind (0 = no limit)
+}
+
+// SetContentChecks enables checks that linked assets match their tag
+// (img → image/*, script → JavaScript, stylesheet → text/css) and are not
+// empty, and flags assets larger than maxSizes (bytes, keyed by Asset* kind)
+func (v *Validator) SetContentChecks(checkTypes bool, maxSizes map[string]int64) {
+	v.content = &contentChecks{
+		client:     v.client,
+		checkTypes: checkTypes,
+		maxSizes:   maxSizes,
+	}
+}
+
+// assetKind classifies a link by the kind of resource its tag expects
+func assetKind(link fetcher.Link) string {
+	switch {
+	case link.Tag == "img" || link.Tag == "image:loc" || link.Tag == "video:thumbnail_loc":
+		return AssetImage
+	case link.Tag == "script":
+		return AssetScript
+	case link.Tag == "link" && link.HasRel("stylesheet"):
+		return AssetStylesheet
+	}
+	return ""
+}
+
+// apply checks a successful response against the asset rules, updating result
+func (c *contentChecks) apply(link fetcher.Link, resp *fetcher.Response, result *Result) {
+	kind := assetKind(link)
+	if kind == "" || resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return
+	}
+
+	if c.checkTypes {
+		// Many servers answer HEAD with Content-Length: 0, so confirm with a GET
+		if resp.ContentLength == 0 {
+			if get := c.client.Get(link.URL); get.Error == nil && get.StatusCode >= 200 && get.StatusCode < 300 {
+				resp = get
+				result.ContentLength = get.ContentLength
+				if get.ContentType != "" {
+					result.ContentType = get.ContentType
+				}
+			}
+		}
+		if resp.ContentLength == 0 {
+			result.IsBroken = true
+			result.Status = fmt.Sprintf("Empty response for %s", kind)
+			return
+		}
+
+		if expected, ok := expectedTypeMatches(kind, resp.ContentType); !ok {
+			result.IsBroken = true
+			result.Status = fmt.Sprintf("Wrong content type %q (expected %s)", resp.ContentType, expected)
+			return
+		}
+	}
+
+	if limit := c.maxSizes[kind]; limit > 0 && resp.ContentLength > limit {
+		result.Oversize = true
+	}
+}
+
+// expectedTypeMatches reports whether contentType suits an asset kind, along
+// with a description of what was expected
+// A missing Content-Type is not treated as a mismatch
+func expectedTypeMatches(kind, contentType string) (string, bool) {
+	switch kind {
+	case AssetImage:
+		return "image/*", contentType == "" || strings.HasPrefix(contentType, "image/")
+	case AssetScript:
+		return "JavaScript", contentType == "" || javascriptTypes[contentType]
+	case AssetStylesheet:
+		return "text/css", contentType == "" || contentType == "text/css"
+	}
+	return "", true
+}
+
+// formatBytes formats a byte count for humans
+func formatBytes(n int64) string {
+	switch {
+	case n >= 1024*1024:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1024*1024))
+	case n >= 1024:
+		return fmt.Sprintf("%.1f KB", float64(n)/1024)
+	}
+	return fmt.Sprintf("%d B", n)
+}
@@ -0,0 +1,341 @@
+package validator
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+
+	"linkchex/internal/fetcher"
+)
+
+// LinkGraph is the site's internal <a> link graph built from a crawl
+type LinkGraph struct {
+	Root         string      // Homepage that click depth is measured from
+	Nodes        []GraphNode // Sorted by URL
+	Edges        []GraphEdge `json:"-"` // Exported via DOT/GraphML instead
+	Orphans      []string    // Sitemap pages no other crawled page links to
+	NotInSitemap []string    // Internal pages that are linked but missing from the sitemap
+	Unreachable  []string    // Linked sitemap pages that still can't be reached from Root (orphans excluded)
+	DepthCounts  map[int]int // Number of pages at each click depth
+}
+
+// GraphNode is a page in the link graph
+type GraphNode struct {
+	URL       string
+	InSitemap bool
+	Crawled   bool // Whether its outbound links are known
+	Depth     int  // Clicks from Root, -1 if unreachable
+	Inbound   int  // Distinct pages linking here
+	Outbound  int  // Distinct pages linked from here
+}
+
+// GraphEdge is an internal link from one page to another
+type GraphEdge struct {
+	Source string
+	Target string
+}
+
+// CrawlGraphPage validates the links on a page the link graph needs but the
+// run doesn't cover, such as a homepage missing from the sitemap. The results
+// are only meant for AnalyzeLinkGraph and never counted in a report
+func (v *Validator) CrawlGraphPage(pageURL string, checkExternal bool) []Result {
+	links, err := v.fetchPageLinks(pageURL)
+	if err != nil {
+		return nil
+	}
+	links = fetcher.FilterLinksByRel(links, v.skipRels)
+	links = fetcher.FilterLinks(links, checkExternal, v.canon)
+	return v.validateLinksInternal(pageURL, links, false)
+}
+
+// AnalyzeLinkGraph builds the internal link graph from the report's results,
+// plus extra results from pages crawled for the graph only, and computes
+// orphans, pages missing from the sitemap, and click depth from root.
+// Only successful internal <a> links to HTML pages become edges, pointing at
+// where the link ends up after redirects, and pages are identified by their
+// canonical URL.
+// The graph is attached as report.LinkGraph
+func (v *Validator) AnalyzeLinkGraph(report *ValidationReport, sitemapURLs []string, root string, extra []Result) *LinkGraph {
+	root = v.canon.Canonical(root)
+	graph := &LinkGraph{
+		Root:        root,
+		DepthCounts: make(map[int]int),
+	}
+
+	nodes := make(map[string]*GraphNode)
+	node := func(pageURL string) *GraphNode {
+		n, ok := nodes[pageURL]
+		if !ok {
+			n = &GraphNode{URL: pageURL, Depth: -1}
+			nodes[pageURL] = n
+		}
+		return n
+	}
+
+	for _, pageURL := range sitemapURLs {
+		node(v.canon.Canonical(pageURL)).InSitemap = true
+	}
+	v.pagesMutex.Lock()
+	for _, page := range v.pages {
+		if page.Error == nil {
+			node(v.canon.Canonical(page.URL)).Crawled = true
+		}
+	}
+	v.pagesMutex.Unlock()
+	node(root)
+
+	// Collect distinct edges between pages
+	seen := make(map[GraphEdge]bool)
+	outbound := make(map[string][]string)
+	for _, result := range append(report.Results[:len(report.Results):len(report.Results)], extra...) {
+		if result.Tag != "a" || result.IsExternal || result.IsBroken || result.StatusCode == 0 || !isHTML(result.ContentType) {
+			continue
+		}
+
+		target, ok := graphTarget(result)
+		if !ok {
+			continue
+		}
+		edge := GraphEdge{Source: v.canon.Canonical(result.SourceURL), Target: v.canon.Canonical(target)}
+		if edge.Source == edge.Target || seen[edge] {
+			continue
+		}
+		seen[edge] = true
+
+		graph.Edges = append(graph.Edges, edge)
+		outbound[edge.Source] = append(outbound[edge.Source], edge.Target)
+		node(edge.Source).Outbound++
+		node(edge.Target).Inbound++
+	}
+
+	// Breadth-first search from the homepage gives the click depth
+	node(root).Depth = 0
+	queue := []string{root}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, target := range outbound[current] {
+			if next := nodes[target]; next.Depth < 0 {
+				next.Depth = nodes[current].Depth + 1
+				queue = append(queue, target)
+			}
+		}
+	}
+
+	for _, n := range nodes {
+		graph.Nodes = append(graph.Nodes, *n)
+	}
+	sort.Slice(graph.Nodes, func(i, j int) bool {
+		return graph.Nodes[i].URL < graph.Nodes[j].URL
+	})
+	sort.Slice(graph.Edges, func(i, j int) bool {
+		if graph.Edges[i].Source != graph.Edges[j].Source {
+			return graph.Edges[i].Source < graph.Edges[j].Source
+		}
+		return graph.Edges[i].Target < graph.Edges[j].Target
+	})
+
+	for _, n := range graph.Nodes {
+		if n.Depth >= 0 {
+			graph.DepthCounts[n.Depth]++
+		}
+		if n.URL == root {
+			continue
+		}
+		if n.InSitemap && n.Inbound == 0 {
+			graph.Orphans = append(graph.Orphans, n.URL)
+		}
+		if !n.InSitemap && n.Inbound > 0 {
+			graph.NotInSitemap = append(graph.NotInSitemap, n.URL)
+		}
+		if n.InSitemap && n.Depth < 0 && n.Inbound > 0 {
+			graph.Unreachable = append(graph.Unreachable, n.URL)
+		}
+	}
+
+	report.LinkGraph = graph
+	return graph
+}
+
+// graphTarget returns the page an internal link leads to after redirects
+// Links redirected off the site lead to no page in the graph
+func graphTarget(result Result) (string, bool) {
+	if result.finalURL == "" || result.finalURL == result.TargetURL {
+		return result.TargetURL, true
+	}
+
+	final, err := url.Parse(result.finalURL)
+	if err != nil {
+		return "", false
+	}
+	for _, internal := range []string{result.TargetURL, result.SourceURL} {
+		if u, err := url.Parse(internal); err == nil && strings.EqualFold(u.Hostname(), final.Hostname()) {
+			return result.finalURL, true
+		}
+	}
+	return "", false
+}
+
+// WriteDOT writes the graph in Graphviz DOT format
+// Pages missing from the sitemap are dashed and orphans are highlighted
+func (g *LinkGraph) WriteDOT(w io.Writer) error {
+	var sb strings.Builder
+
+	sb.WriteString("digraph linkchex {\n")
+	sb.WriteString("  rankdir=LR;\n")
+	sb.WriteString("  node [shape=box, fontsize=10];\n")
+
+	orphans := make(map[string]bool)
+	for _, orphan := range g.Orphans {
+		orphans[orphan] = true
+	}
+
+	for _, n := range g.Nodes {
+		var attrs []string
+		attrs = append(attrs, fmt.Sprintf(`label="%s\ndepth %s"`, dotEscape(n.URL), depthLabel(n.Depth)))
+		if n.URL == g.Root {
+			attrs = append(attrs, "shape=doubleoctagon")
+		}
+		if !n.InSitemap {
+			attrs = append(attrs, "style=dashed")
+		}
+		if orphans[n.URL] {
+			attrs = append(attrs, "color=red")
+		}
+		sb.WriteString(fmt.Sprintf("  %s [%s];\n", dotQuote(n.URL), strings.Join(attrs, ", ")))
+	}
+
+	for _, e := range g.Edges {
+		sb.WriteString(fmt.Sprintf("  %s -> %s;\n", dotQuote(e.Source), dotQuote(e.Target)))
+	}
+
+	sb.WriteString("}\n")
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// graphML mirrors the subset of the GraphML schema linkchex writes
+type graphML struct {
+	XMLName xml.Name     `xml:"graphml"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	Keys    []graphMLKey `xml:"key"`
+	Graph   graphMLGraph `xml:"graph"`
+}
+
+type graphMLKey struct {
+	ID   string `xml:"id,attr"`
+	For  string `xml:"for,attr"`
+	Name string `xml:"attr.name,attr"`
+	Type string `xml:"attr.type,attr"`
+}
+
+type graphMLGraph struct {
+	ID          string        `xml:"id,attr"`
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Nodes       []graphMLNode `xml:"node"`
+	Edges       []graphMLEdge `xml:"edge"`
+}
+
+type graphMLNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphMLData `xml:"data"`
+}
+
+type graphMLEdge struct {
+	Source string `xml:"source,attr"`
+	Target string `xml:"target,attr"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// WriteGraphML writes the graph in GraphML format, with sitemap membership,
+// depth and link counts as node attributes
+func (g *LinkGraph) WriteGraphML(w io.Writer) error {
+	doc := graphML{
+		XMLNS: "http://graphml.graphdrawing.org/xmlns",
+		Keys: []graphMLKey{
+			{ID: "url", For: "node", Name: "url", Type: "string"},
+			{ID: "in_sitemap", For: "node", Name: "in_sitemap", Type: "boolean"},
+			{ID: "depth", For: "node", Name: "depth", Type: "int"},
+			{ID: "inbound", For: "node", Name: "inbound", Type: "int"},
+			{ID: "outbound", For: "node", Name: "outbound", Type: "int"},
+		},
+		Graph: graphMLGraph{ID: "linkchex", EdgeDefault: "directed"},
+	}
+
+	// GraphML ids are referenced by edges, so use short stable ids
+	ids := make(map[string]string, len(g.Nodes))
+	for i, n := range g.Nodes {
+		ids[n.URL] = fmt.Sprintf("n%d", i)
+		doc.Graph.Nodes = append(doc.Graph.Nodes, graphMLNode{
+			ID: ids[n.URL],
+			Data: []graphMLData{
+				{Key: "url", Value: n.URL},
+				{Key: "in_sitemap", Value: fmt.Sprintf("%t", n.InSitemap)},
+				{Key: "depth", Value: fmt.Sprintf("%d", n.Depth)},
+				{Key: "inbound", Value: fmt.Sprintf("%d", n.Inbound)},
+				{Key: "outbound", Value: fmt.Sprintf("%d", n.Outbound)},
+			},
+		})
+	}
+	for _, e := range g.Edges {
+		doc.Graph.Edges = append(doc.Graph.Edges, graphMLEdge{Source: ids[e.Source], Target: ids[e.Target]})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// WriteGraphFile writes the graph to a file in "dot" or "graphml" format
+func WriteGraphFile(graph *LinkGraph, format, filename string) error {
+	var sb strings.Builder
+
+	var err error
+	switch format {
+	case "dot":
+		err = graph.WriteDOT(&sb)
+	case "graphml":
+		err = graph.WriteGraphML(&sb)
+	default:
+		return fmt.Errorf("unsupported graph format: %s", format)
+	}
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filename, []byte(sb.String()), 0644)
+}
+
+// dotQuote quotes a string as a DOT identifier
+func dotQuote(s string) string {
+	return `"` + dotEscape(s) + `"`
+}
+
+// dotEscape escapes backslashes and quotes for use inside a DOT string
+func dotEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s)
+}
+
+// depthLabel formats a click depth, showing unreachable pages as "-"
+func depthLabel(depth int) string {
+	if depth < 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%d", depth)
+}
@@ -0,0 +1,203 @@
+package validator
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"linkchex/internal/fetcher"
+	"linkchex/internal/sitemap"
+)
+
+// Hreflang issue types
+const (
+	HreflangMissingReturn   = "missing-return-link"
+	HreflangMissingXDefault = "missing-x-default"
+	HreflangBrokenAlternate = "broken-alternate"
+	HreflangInvalidCode     = "invalid-hreflang"
+)
+
+// hreflangPattern matches a language code with optional script and region
+var hreflangPattern = regexp.MustCompile(`^[a-zA-Z]{2,3}(-[a-zA-Z]{4})?(-([a-zA-Z]{2}|[0-9]{3}))?$`)
+
+// HreflangFinding is a single problem with a page's language alternates
+type HreflangFinding struct {
+	URL       string // Page declaring the alternate
+	Hreflang  string
+	Alternate string
+	Issue     string // One of the Hreflang* constants
+	Detail    string
+}
+
+// HreflangReport summarizes hreflang validation across all pages
+type HreflangReport struct {
+	PagesChecked      int
+	AlternatesChecked int
+	Findings          []HreflangFinding
+	IssuesByType      map[string]int
+}
+
+// CheckHreflang validates hreflang alternates declared in the sitemap and in
+// page <head>s: each alternate must resolve, link back to the page, and every
+// alternate set must include x-default. Findings are attached as report.Hreflang
+func (v *Validator) CheckHreflang(entries []sitemap.URL, report *ValidationReport) *HreflangReport {
+	hreflang := &HreflangReport{
+		IssuesByType: make(map[string]int),
+	}
+
+	// Merge sitemap and page alternates per page
+	alternates := make(map[string][]fetcher.Alternate)
+	addAlternate := func(pageURL string, alt fetcher.Alternate) {
+		for _, existing := range alternates[pageURL] {
+			if existing.URL == alt.URL && strings.EqualFold(existing.Hreflang, alt.Hreflang) {
+				return
+			}
+		}
+		alternates[pageURL] = append(alternates[pageURL], alt)
+	}
+	for _, entry := range entries {
+		for _, alt := range entry.Alternates {
+			if alt.Href != "" && alt.Hreflang != "" && (alt.Rel == "" || strings.EqualFold(alt.Rel, "alternate")) {
+				addAlternate(entry.Loc, fetcher.Alternate{Hreflang: alt.Hreflang, URL: strings.TrimSpace(alt.Href)})
+			}
+		}
+	}
+	for _, page := range report.Pages {
+		for _, alt := range page.Alternates {
+			addAlternate(page.URL, alt)
+		}
+	}
+
+	pageURLs := make([]string, 0, len(alternates))
+	for pageURL := range alternates {
+		pageURLs = append(pageURLs, pageURL)
+	}
+	sort.Strings(pageURLs)
+
+	var mu sync.Mutex
+	add := func(finding HreflangFinding) {
+		mu.Lock()
+		hreflang.Findings = append(hreflang.Findings, finding)
+		hreflang.IssuesByType[finding.Issue]++
+		mu.Unlock()
+	}
+
+	// Alternates of pages outside the crawl are fetched on demand
+	fetched := &alternateCache{known: alternates}
+
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, v.concurrency)
+
+	for _, pageURL := range pageURLs {
+		alts := alternates[pageURL]
+		hreflang.PagesChecked++
+		hreflang.AlternatesChecked += len(alts)
+
+		wg.Add(1)
+		go func(pageURL string, alts []fetcher.Alternate) {
+			defer wg.Done()
+			semaphore <- struct{}{}        // Acquire
+			defer func() { <-semaphore }() // Release
+
+			hasXDefault := false
+			for _, alt := range alts {
+				if strings.EqualFold(alt.Hreflang, "x-default") {
+					hasXDefault = true
+				} else if !hreflangPattern.MatchString(alt.Hreflang) {
+					add(HreflangFinding{URL: pageURL, Hreflang: alt.Hreflang, Alternate: alt.URL, Issue: HreflangInvalidCode,
+						Detail: fmt.Sprintf("%q is not a valid language/region code", alt.Hreflang)})
+				}
+
+				if stripFragment(alt.URL) == stripFragment(pageURL) {
+					continue // Self-reference
+				}
+
+				result := v.validateLink(pageURL, fetcher.Link{URL: alt.URL, Tag: "link", Attr: "href", Rel: []string{"alternate"}})
+				if result.IsBroken {
+					detail := fmt.Sprintf("alternate returned status %d", result.StatusCode)
+					if result.Error != nil {
+						detail = result.Error.Error()
+					}
+					add(HreflangFinding{URL: pageURL, Hreflang: alt.Hreflang, Alternate: alt.URL, Issue: HreflangBrokenAlternate, Detail: detail})
+					continue
+				}
+
+				if !linksBack(v.alternatesOf(fetched, alt.URL), pageURL) {
+					add(HreflangFinding{URL: pageURL, Hreflang: alt.Hreflang, Alternate: alt.URL, Issue: HreflangMissingReturn,
+						Detail: fmt.Sprintf("%s does not list %s as an alternate", alt.URL, pageURL)})
+				}
+			}
+
+			if !hasXDefault {
+				add(HreflangFinding{URL: pageURL, Issue: HreflangMissingXDefault, Detail: "no x-default alternate declared"})
+			}
+		}(pageURL, alts)
+	}
+
+	wg.Wait()
+
+	sort.SliceStable(hreflang.Findings, func(i, j int) bool {
+		a, b := hreflang.Findings[i], hreflang.Findings[j]
+		if a.Issue != b.Issue {
+			return a.Issue < b.Issue
+		}
+		if a.URL != b.URL {
+			return a.URL < b.URL
+		}
+		return a.Hreflang < b.Hreflang
+	})
+
+	report.Hreflang = hreflang
+	return hreflang
+}
+
+// alternateCache holds known alternates per page plus those fetched on demand
+type alternateCache struct {
+	known   map[string][]fetcher.Alternate // Read-only after construction
+	fetched map[string][]fetcher.Alternate
+	mu      sync.Mutex
+}
+
+// alternatesOf returns a page's declared alternates, fetching the page if it wasn't crawled
+func (v *Validator) alternatesOf(cache *alternateCache, pageURL string) []fetcher.Alternate {
+	if alts, ok := cache.known[pageURL]; ok {
+		return alts
+	}
+
+	cache.mu.Lock()
+	alts, ok := cache.fetched[pageURL]
+	cache.mu.Unlock()
+	if ok {
+		return alts
+	}
+
+	if v.client.Allowed(pageURL) {
+		resp := v.client.Get(pageURL)
+		if resp.Error == nil && resp.StatusCode == 200 {
+			if meta, err := fetcher.ExtractPageMeta(resp.Body, resp.FinalURL); err == nil {
+				alts = meta.Alternates
+			}
+		}
+	}
+
+	cache.mu.Lock()
+	if cache.fetched == nil {
+		cache.fetched = make(map[string][]fetcher.Alternate)
+	}
+	cache.fetched[pageURL] = alts
+	cache.mu.Unlock()
+
+	return alts
+}
+
+// linksBack reports whether any alternate points at pageURL
+func linksBack(alts []fetcher.Alternate, pageURL string) bool {
+	for _, alt := range alts {
+		if stripFragment(alt.URL) == stripFragment(pageURL) {
+			return true
+		}
+	}
+	return false
+}
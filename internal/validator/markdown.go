@@ -0,0 +1,188 @@
+package validator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// DefaultMarkdownLimit keeps markdown reports under GitHub's 65536 character
+// limit for pull request comments, with room for a surrounding message
+const DefaultMarkdownLimit = 60000
+
+// markdownTopTargets is how many of the most widespread broken targets are listed
+const markdownTopTargets = 10
+
+// FormatMarkdown formats the report as GitHub-flavored markdown: a summary
+// table followed by a collapsible section per page with broken links.
+// Page sections that don't fit in maxBytes are omitted with a note (0 = no limit)
+func FormatMarkdown(report *ValidationReport, maxBytes int) string {
+	var sb strings.Builder
+
+	// Header and summary
+	if report.BrokenLinks == 0 {
+		sb.WriteString("## ✅ Link Check: all links valid\n\n")
+	} else {
+		sb.WriteString(fmt.Sprintf("## ❌ Link Check: %d broken link(s)\n\n", report.BrokenLinks))
+	}
+
+	sb.WriteString("| | Count |\n")
+	sb.WriteString("|---|---:|\n")
+	sb.WriteString(fmt.Sprintf("| Pages processed | %d |\n", report.PagesProcessed))
+	sb.WriteString(fmt.Sprintf("| Links checked | %d |\n", report.TotalLinks))
+	sb.WriteString(fmt.Sprintf("| Unique URLs | %d |\n", report.UniqueURLs))
+	sb.WriteString(fmt.Sprintf("| ✅ Success | %d (%.1f%%) |\n", report.SuccessLinks, percentage(report.SuccessLinks, report.TotalLinks)))
+	sb.WriteString(fmt.Sprintf("| ❌ Broken | %d (%.1f%%) |\n", report.BrokenLinks, percentage(report.BrokenLinks, report.TotalLinks)))
+	sb.WriteString(fmt.Sprintf("| ⚠️ Redirects | %d (%.1f%%) |\n", report.WarningLinks, percentage(report.WarningLinks, report.TotalLinks)))
+	if report.Soft404Links > 0 {
+		sb.WriteString(fmt.Sprintf("| Soft 404s | %d |\n", report.Soft404Links))
+	}
+	if report.OversizeLinks > 0 {
+		sb.WriteString(fmt.Sprintf("| Heavy assets | %d |\n", report.OversizeLinks))
+	}
+	if report.FlakyLinks > 0 {
+		sb.WriteString(fmt.Sprintf("| 🔁 Flaky (worked on re-check) | %d |\n", report.FlakyLinks))
+	}
+	if report.Security != nil {
+		sb.WriteString(fmt.Sprintf("| Security findings | %d |\n", len(report.Security.Findings)))
+	}
+	if report.TLS != nil {
+		sb.WriteString(fmt.Sprintf("| TLS issues | %d |\n", len(report.TLS.Findings)))
+	}
+	if report.PageChecks != nil {
+		sb.WriteString(fmt.Sprintf("| Page check issues | %d |\n", len(report.PageChecks.Findings)))
+	}
+	if report.SitemapAudit != nil {
+		sb.WriteString(fmt.Sprintf("| Sitemap audit issues | %d |\n", len(report.SitemapAudit.Findings)))
+	}
+	if report.Hreflang != nil {
+		sb.WriteString(fmt.Sprintf("| Hreflang issues | %d |\n", len(report.Hreflang.Findings)))
+	}
+	if report.LinkGraph != nil {
+		sb.WriteString(fmt.Sprintf("| Orphan pages | %d |\n", len(report.LinkGraph.Orphans)))
+	}
+	sb.WriteString(fmt.Sprintf("| Duration | %s |\n", report.Duration.Round(time.Millisecond)))
+	sb.WriteString("\n")
+
+	// Most widespread broken targets
+	if len(report.BrokenTargets) > 0 {
+		sb.WriteString("### Most common broken targets\n\n")
+		sb.WriteString("| Target | Status | Occurrences |\n")
+		sb.WriteString("|---|---|---:|\n")
+		for i, target := range report.BrokenTargets {
+			if i == markdownTopTargets {
+				break
+			}
+			sb.WriteString(fmt.Sprintf("| %s | %s | %d on %d page(s) |\n",
+				markdownLink(target.URL), markdownCell(targetStatus(target.StatusCode, target.Status, target.Error)),
+				len(target.Occurrences), target.Pages))
+		}
+		sb.WriteString("\n")
+	}
+
+	sections := markdownPageSections(report)
+	if len(sections) > 0 {
+		sb.WriteString("### Broken links by page\n\n")
+	}
+
+	// Add page sections until the limit, leaving room for the truncation note
+	const noteRoom = 200
+	for i, section := range sections {
+		if maxBytes > 0 && sb.Len()+len(section)+noteRoom > maxBytes {
+			sb.WriteString(fmt.Sprintf("_… %d more page(s) with broken links omitted to stay under %d bytes. See the full report for details._\n", len(sections)-i, maxBytes))
+			break
+		}
+		sb.WriteString(section)
+	}
+
+	return truncateBytes(sb.String(), maxBytes)
+}
+
+// markdownPageSections renders one collapsible section per page with broken
+// links, pages with the most broken links first
+func markdownPageSections(report *ValidationReport) []string {
+	byPage := make(map[string][]Result)
+	var pages []string
+	for _, result := range report.Results {
+		if !result.IsBroken {
+			continue
+		}
+		if _, ok := byPage[result.SourceURL]; !ok {
+			pages = append(pages, result.SourceURL)
+		}
+		byPage[result.SourceURL] = append(byPage[result.SourceURL], result)
+	}
+
+	sort.SliceStable(pages, func(i, j int) bool {
+		if len(byPage[pages[i]]) != len(byPage[pages[j]]) {
+			return len(byPage[pages[i]]) > len(byPage[pages[j]])
+		}
+		return pages[i] < pages[j]
+	})
+
+	sections := make([]string, 0, len(pages))
+	for _, page := range pages {
+		var sb strings.Builder
+		sb.WriteString("<details>\n")
+		sb.WriteString(fmt.Sprintf("<summary><code>%s</code> — %d broken</summary>\n\n", htmlEscapeMarkdown(truncate(page, 100)), len(byPage[page])))
+		sb.WriteString("| Link | Tag | Text | Status |\n")
+		sb.WriteString("|---|---|---|---|\n")
+		for _, result := range byPage[page] {
+			errorStr := ""
+			if result.Error != nil {
+				errorStr = result.Error.Error()
+			}
+			sb.WriteString(fmt.Sprintf("| %s | `<%s>` | %s | %s |\n",
+				markdownLink(result.TargetURL),
+				result.Tag,
+				markdownCell(truncate(result.LinkText, 40)),
+				markdownCell(truncate(targetStatus(result.StatusCode, result.Status, errorStr), 80))))
+		}
+		sb.WriteString("\n</details>\n\n")
+		sections = append(sections, sb.String())
+	}
+
+	return sections
+}
+
+// targetStatus describes why a link is broken
+func targetStatus(statusCode int, status, errorStr string) string {
+	if errorStr != "" {
+		return errorStr
+	}
+	if strings.HasPrefix(status, fmt.Sprintf("%d", statusCode)) {
+		return status
+	}
+	return fmt.Sprintf("%d %s", statusCode, status)
+}
+
+// markdownLink renders a URL as a link whose text is safe inside a table cell
+func markdownLink(rawURL string) string {
+	target := strings.NewReplacer(" ", "%20", "(", "%28", ")", "%29", "|", "%7C").Replace(rawURL)
+	return fmt.Sprintf("[%s](%s)", markdownCell(truncate(rawURL, 80)), target)
+}
+
+// markdownCell escapes text for use inside a markdown table cell
+func markdownCell(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	return strings.NewReplacer("|", "\\|", "[", "\\[", "]", "\\]", "<", "&lt;", ">", "&gt;", "`", "\\`").Replace(s)
+}
+
+// htmlEscapeMarkdown escapes text placed inside inline HTML such as <summary>
+func htmlEscapeMarkdown(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
+
+// truncateBytes cuts s to at most maxBytes without splitting a UTF-8 character
+func truncateBytes(s string, maxBytes int) string {
+	if maxBytes <= 0 || len(s) <= maxBytes {
+		return s
+	}
+	cut := maxBytes
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut]
+}
@@ -0,0 +1,59 @@
+package validator
+
+import (
+	"net/url"
+	"strings"
+
+	"linkchex/internal/fetcher"
+	"linkchex/internal/sitemap"
+)
+
+// SetSitemapMedia registers image and video URLs from sitemap extensions to be
+// validated alongside each page's links, attributed to the page they belong to
+func (v *Validator) SetSitemapMedia(entries []sitemap.URL) {
+	v.media = make(map[string][]fetcher.Link)
+
+	for _, entry := range entries {
+		page, err := url.Parse(entry.Loc)
+		if err != nil {
+			continue
+		}
+
+		add := func(mediaURL, tag, text string) {
+			mediaURL = strings.TrimSpace(mediaURL)
+			if mediaURL == "" {
+				return
+			}
+			target, err := url.Parse(mediaURL)
+			if err != nil {
+				return
+			}
+			absolute := page.ResolveReference(target)
+			v.media[entry.Loc] = append(v.media[entry.Loc], fetcher.Link{
+				URL:        absolute.String(),
+				Tag:        tag,
+				Attr:       "loc",
+				Text:       text,
+				IsExternal: page.Host != absolute.Host,
+			})
+		}
+
+		for _, image := range entry.Images {
+			add(image.Loc, "image:loc", "")
+		}
+		for _, video := range entry.Videos {
+			add(video.ContentLoc, "video:content_loc", video.Title)
+			add(video.PlayerLoc, "video:player_loc", video.Title)
+			add(video.ThumbnailLoc, "video:thumbnail_loc", video.Title)
+		}
+	}
+}
+
+// validateMedia validates the sitemap media registered for a page
+func (v *Validator) validateMedia(pageURL string, checkExternal bool) []Result {
+	links := fetcher.FilterLinks(v.media[pageURL], checkExternal, v.canon)
+	if len(links) == 0 {
+		return nil
+	}
+	return v.validateLinksInternal(pageURL, links, false)
+}
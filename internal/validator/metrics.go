@@ -0,0 +1,189 @@
+package validator
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/url"
+	"strings"
+	"sync/atomic"
+
+	"linkchex/internal/fetcher"
+	"linkchex/internal/metrics"
+)
+
+// Broken link classes reported in linkchex_links_broken_total
+const (
+	BrokenClass4xx        = "4xx"
+	BrokenClass5xx        = "5xx"
+	BrokenClassSoft404    = "soft404"
+	BrokenClassTimeout    = "timeout"
+	BrokenClassDNS        = "dns"
+	BrokenClassTLS        = "tls"
+	BrokenClassConnection = "connection"
+	BrokenClassOther      = "other"
+)
+
+// runMetrics are the validator's Prometheus metrics, updated as links are checked
+type runMetrics struct {
+	linksChecked   *metrics.Counter
+	linksBroken    *metrics.Counter
+	linksFlaky     *metrics.Counter
+	latency        *metrics.Histogram
+	cacheLookups   *metrics.Counter
+	pagesProcessed *metrics.Counter
+	pagesTotal     *metrics.Gauge
+	cacheHits      atomic.Int64
+	cacheMisses    atomic.Int64
+}
+
+// SetMetrics registers the validator's metrics and records to them during
+// validation, so they can be scraped while a long run is in progress
+func (v *Validator) SetMetrics(registry *metrics.Registry) {
+	m := &runMetrics{
+		linksChecked: registry.Counter("linkchex_links_checked_total",
+			"Links checked, by link type (internal or external)", "type"),
+		linksBroken: registry.Counter("linkchex_links_broken_total",
+			"Broken links, by class (4xx, 5xx, soft404, timeout, dns, tls, connection, other)", "class"),
+		linksFlaky: registry.Counter("linkchex_links_flaky_total",
+			"Broken links that worked when re-verified (also counted in linkchex_links_broken_total)", "type"),
+		latency: registry.Histogram("linkchex_request_duration_seconds",
+			"Latency of link and page requests, by host", metrics.DefaultLatencyBuckets, "host"),
+		cacheLookups: registry.Counter("linkchex_cache_lookups_total",
+			"Link result cache lookups, by result (hit or miss)", "result"),
+		pagesProcessed: registry.Counter("linkchex_pages_processed_total",
+			"Pages processed, by status (ok, failed or skipped)", "status"),
+		pagesTotal: registry.Gauge("linkchex_pages_total",
+			"Pages to process in this run"),
+	}
+	registry.GaugeFunc("linkchex_cache_hit_ratio",
+		"Share of link checks answered from the cache",
+		func() float64 {
+			hits, misses := m.cacheHits.Load(), m.cacheMisses.Load()
+			if hits+misses == 0 {
+				return 0
+			}
+			return float64(hits) / float64(hits+misses)
+		})
+	v.metrics = m
+}
+
+// recordCache counts a cache lookup
+func (m *runMetrics) recordCache(hit bool) {
+	if m == nil {
+		return
+	}
+	if hit {
+		m.cacheHits.Add(1)
+		m.cacheLookups.Inc("hit")
+	} else {
+		m.cacheMisses.Add(1)
+		m.cacheLookups.Inc("miss")
+	}
+}
+
+// recordRequest observes the latency of a request that was actually made
+func (m *runMetrics) recordRequest(resp *fetcher.Response) {
+	if m == nil {
+		return
+	}
+	host := "unknown"
+	if parsed, err := url.Parse(resp.URL); err == nil && parsed.Host != "" {
+		host = parsed.Host
+	}
+	m.latency.Observe(resp.Duration.Seconds(), host)
+}
+
+// recordResult counts a checked link and, if broken, its class
+func (m *runMetrics) recordResult(result Result) {
+	if m == nil {
+		return
+	}
+	linkType := "internal"
+	if result.IsExternal {
+		linkType = "external"
+	}
+	m.linksChecked.Inc(linkType)
+	if result.IsBroken {
+		m.linksBroken.Inc(BrokenClass(result))
+	}
+}
+
+// recordFlaky counts a broken link that worked when re-verified
+func (m *runMetrics) recordFlaky(result Result) {
+	if m == nil {
+		return
+	}
+	linkType := "internal"
+	if result.IsExternal {
+		linkType = "external"
+	}
+	m.linksFlaky.Inc(linkType)
+}
+
+// recordFailedPage counts a page that couldn't be crawled, classified by its
+// HTTP status when the server answered with an error
+func (m *runMetrics) recordFailedPage(result Result, statusCode int) {
+	if m == nil {
+		return
+	}
+	if statusCode >= 400 {
+		result.StatusCode = statusCode
+		result.Error = nil
+	}
+	m.recordResult(result)
+	m.recordPage("failed")
+}
+
+// recordPage counts a processed page
+func (m *runMetrics) recordPage(status string) {
+	if m == nil {
+		return
+	}
+	m.pagesProcessed.Inc(status)
+}
+
+// BrokenClass classifies why a link is broken
+func BrokenClass(result Result) string {
+	switch {
+	case result.Soft404:
+		return BrokenClassSoft404
+	case result.Error == nil && result.StatusCode >= 500:
+		return BrokenClass5xx
+	case result.Error == nil && result.StatusCode >= 400:
+		return BrokenClass4xx
+	case result.Error == nil:
+		return BrokenClassOther
+	}
+
+	err := result.Error
+	var dnsErr *net.DNSError
+	var netErr net.Error
+	switch {
+	case errors.As(err, &dnsErr):
+		return BrokenClassDNS
+	case fetcher.IsCertificateError(err):
+		return BrokenClassTLS
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return BrokenClassTimeout
+	}
+
+	// Errors restored from an incremental state file are plain strings
+	msg := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(msg, "no such host"), strings.Contains(msg, "server misbehaving"):
+		return BrokenClassDNS
+	case strings.Contains(msg, "certificate"), strings.Contains(msg, "tls:"), strings.Contains(msg, "x509:"):
+		return BrokenClassTLS
+	case strings.Contains(msg, "timeout"), strings.Contains(msg, "deadline exceeded"):
+		return BrokenClassTimeout
+	case strings.Contains(msg, "connection refused"), strings.Contains(msg, "connection reset"), strings.Contains(msg, "eof"):
+		return BrokenClassConnection
+	}
+
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		return BrokenClassConnection
+	}
+	return BrokenClassOther
+}
@@ -0,0 +1,175 @@
+package validator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"linkchex/internal/fetcher"
+)
+
+// Page check issue types
+const (
+	PageMissingTitle         = "missing-title"
+	PageMultipleTitle        = "multiple-title"  // Several <title> elements on one page
+	PageDuplicateTitle       = "duplicate-title" // Title shared with other pages
+	PageMissingDescription   = "missing-description"
+	PageMultipleDescription  = "multiple-description"  // Several meta descriptions on one page
+	PageDuplicateDescription = "duplicate-description" // Description shared with other pages
+	PageMissingAlt           = "missing-alt"
+	PageMultipleH1           = "multiple-h1"
+	PageMissingLang          = "missing-lang"
+	PageEmptyLinkText        = "empty-link-text"
+	PageUnsafeTargetBlank    = "unsafe-target-blank"
+)
+
+// duplicateSample is how many of the other pages sharing a title or
+// description a finding lists, so site-wide duplicates don't grow the report
+// quadratically
+const duplicateSample = 3
+
+// PageFinding is a single HTML-structure or SEO problem on a crawled page
+type PageFinding struct {
+	URL     string // Page the problem was found on
+	Issue   string // One of the Page* constants
+	Element string // Offending element, e.g. an image src or link href
+	Detail  string
+}
+
+// PageChecksReport summarizes on-page checks across all crawled pages
+type PageChecksReport struct {
+	PagesChecked int
+	Findings     []PageFinding
+	IssuesByType map[string]int
+}
+
+// pageChecker collects the structure of every crawled page so site-wide
+// duplicates can be found once the crawl is done
+type pageChecker struct {
+	pages map[string]fetcher.PageStructure
+	mu    sync.Mutex
+}
+
+// SetPageChecks enables HTML-structure and SEO checks on crawled pages
+func (v *Validator) SetPageChecks(enabled bool) {
+	if !enabled {
+		v.pageChecks = nil
+		return
+	}
+	v.pageChecks = &pageChecker{
+		pages: make(map[string]fetcher.PageStructure),
+	}
+}
+
+// record stores the structure of a crawled page
+func (c *pageChecker) record(pageURL string, page fetcher.PageStructure) {
+	c.mu.Lock()
+	c.pages[pageURL] = page
+	c.mu.Unlock()
+}
+
+// report runs per-page checks and site-wide duplicate detection
+func (c *pageChecker) report() *PageChecksReport {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	report := &PageChecksReport{
+		PagesChecked: len(c.pages),
+		IssuesByType: make(map[string]int),
+	}
+
+	add := func(pageURL, issue, element, detail string) {
+		report.Findings = append(report.Findings, PageFinding{
+			URL:     pageURL,
+			Issue:   issue,
+			Element: element,
+			Detail:  detail,
+		})
+		report.IssuesByType[issue]++
+	}
+
+	titles := make(map[string][]string)
+	descriptions := make(map[string][]string)
+
+	for pageURL, page := range c.pages {
+		switch {
+		case page.Title == "":
+			add(pageURL, PageMissingTitle, "", "page has no <title> or it is empty")
+		case page.TitleCount > 1:
+			add(pageURL, PageMultipleTitle, page.Title, fmt.Sprintf("page has %d <title> elements", page.TitleCount))
+		}
+		if page.Title != "" {
+			titles[strings.ToLower(page.Title)] = append(titles[strings.ToLower(page.Title)], pageURL)
+		}
+
+		switch {
+		case page.Description == "":
+			add(pageURL, PageMissingDescription, "", "page has no meta description or it is empty")
+		case page.DescriptionCount > 1:
+			add(pageURL, PageMultipleDescription, page.Description, fmt.Sprintf("page has %d meta descriptions", page.DescriptionCount))
+		}
+		if page.Description != "" {
+			descriptions[strings.ToLower(page.Description)] = append(descriptions[strings.ToLower(page.Description)], pageURL)
+		}
+
+		if page.Lang == "" {
+			add(pageURL, PageMissingLang, "<html>", "<html> has no lang attribute")
+		}
+		if page.H1Count > 1 {
+			add(pageURL, PageMultipleH1, "", fmt.Sprintf("page has %d <h1> elements", page.H1Count))
+		}
+		for _, src := range page.ImagesWithoutAlt {
+			add(pageURL, PageMissingAlt, src, "<img> has no alt attribute")
+		}
+		for _, href := range page.EmptyLinks {
+			add(pageURL, PageEmptyLinkText, href, "<a> has no text, label or image alt")
+		}
+		for _, href := range page.UnsafeBlank {
+			add(pageURL, PageUnsafeTargetBlank, href, `target="_blank" without rel="noopener"`)
+		}
+	}
+
+	// Titles and descriptions shared by several pages
+	addDuplicates := func(groups map[string][]string, issue, what string, value func(fetcher.PageStructure) string) {
+		for _, pageURLs := range groups {
+			if len(pageURLs) < 2 {
+				continue
+			}
+			sort.Strings(pageURLs)
+			for _, pageURL := range pageURLs {
+				add(pageURL, issue, value(c.pages[pageURL]), fmt.Sprintf("same %s as %d other page(s): %s", what, len(pageURLs)-1, otherURLs(pageURLs, pageURL)))
+			}
+		}
+	}
+	addDuplicates(titles, PageDuplicateTitle, "title", func(p fetcher.PageStructure) string { return p.Title })
+	addDuplicates(descriptions, PageDuplicateDescription, "description", func(p fetcher.PageStructure) string { return p.Description })
+
+	sort.SliceStable(report.Findings, func(i, j int) bool {
+		a, b := report.Findings[i], report.Findings[j]
+		if a.Issue != b.Issue {
+			return a.Issue < b.Issue
+		}
+		if a.URL != b.URL {
+			return a.URL < b.URL
+		}
+		return a.Element < b.Element
+	})
+
+	return report
+}
+
+// otherURLs lists the first few urls other than the given one
+func otherURLs(urls []string, exclude string) string {
+	others := make([]string, 0, duplicateSample)
+	for _, u := range urls {
+		if u == exclude {
+			continue
+		}
+		if len(others) == duplicateSample {
+			return fmt.Sprintf("%s and %d more", strings.Join(others, ", "), len(urls)-1-duplicateSample)
+		}
+		others = append(others, u)
+	}
+	return strings.Join(others, ", ")
+}
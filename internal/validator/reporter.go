@@ -4,13 +4,18 @@ import (
 	"encoding/csv"
 	"encoding/json"
 	"fmt"
-	"os"
+	"sort"
 	"strings"
 	"time"
 )
 
+// FormatOptions tunes format-specific output
+type FormatOptions struct {
+	MarkdownLimit int // Maximum markdown report size in bytes (0 = no limit)
+}
+
 // FormatReport formats the validation report in the specified format
-func FormatReport(report *ValidationReport, format string) (string, error) {
+func FormatReport(report *ValidationReport, format string, opts FormatOptions) (string, error) {
 	switch format {
 	case "text":
 		return formatText(report), nil
@@ -18,6 +23,8 @@ func FormatReport(report *ValidationReport, format string) (string, error) {
 		return formatJSON(report)
 	case "csv":
 		return formatCSV(report)
+	case "markdown", "md":
+		return FormatMarkdown(report, opts.MarkdownLimit), nil
 	default:
 		return "", fmt.Errorf("unsupported format: %s", format)
 	}
@@ -38,6 +45,15 @@ func formatText(report *ValidationReport) string {
 	sb.WriteString(fmt.Sprintf("✓ Success:         %d (%.1f%%)\n", report.SuccessLinks, percentage(report.SuccessLinks, report.TotalLinks)))
 	sb.WriteString(fmt.Sprintf("✗ Broken:          %d (%.1f%%)\n", report.BrokenLinks, percentage(report.BrokenLinks, report.TotalLinks)))
 	sb.WriteString(fmt.Sprintf("⚠ Warnings:        %d (%.1f%%)\n", report.WarningLinks, percentage(report.WarningLinks, report.TotalLinks)))
+	if report.Soft404Links > 0 {
+		sb.WriteString(fmt.Sprintf("✗ Soft 404s:       %d (counted as broken)\n", report.Soft404Links))
+	}
+	if report.OversizeLinks > 0 {
+		sb.WriteString(fmt.Sprintf("⚠ Heavy Assets:    %d\n", report.OversizeLinks))
+	}
+	if report.FlakyLinks > 0 {
+		sb.WriteString(fmt.Sprintf("↻ Flaky:           %d (broken at first, worked when re-verified)\n", report.FlakyLinks))
+	}
 	sb.WriteString(fmt.Sprintf("Internal Links:    %d\n", report.InternalLinks))
 	if report.CheckExternal {
 		sb.WriteString(fmt.Sprintf("External Links:    %d\n", report.ExternalLinks))
@@ -56,6 +72,21 @@ func formatText(report *ValidationReport) string {
 		sb.WriteString("\n")
 	}
 
+	// Sitemap tree issues
+	if len(report.SitemapIssues) > 0 {
+		sb.WriteString("Sitemap Issues:\n")
+		sb.WriteString("---------------\n")
+		for _, issue := range report.SitemapIssues {
+			sb.WriteString(fmt.Sprintf("\n⚠ %s\n", issue.Sitemap))
+			sb.WriteString(fmt.Sprintf("  Issue:  %s\n", issue.Kind))
+			if issue.Parent != "" {
+				sb.WriteString(fmt.Sprintf("  Parent: %s\n", issue.Parent))
+			}
+			sb.WriteString(fmt.Sprintf("  Detail: %s\n", issue.Error))
+		}
+		sb.WriteString("\n")
+	}
+
 	// Broken links details
 	if report.BrokenLinks > 0 {
 		sb.WriteString("Broken Links:\n")
@@ -78,6 +109,26 @@ func formatText(report *ValidationReport) string {
 		sb.WriteString("\n")
 	}
 
+	// Broken links grouped by target, most widespread first
+	if len(report.BrokenTargets) > 0 {
+		sb.WriteString(formatBrokenTargetsText(report.BrokenTargets))
+	}
+
+	// Links that recovered on re-verification
+	if report.FlakyLinks > 0 {
+		sb.WriteString("Flaky Links:\n")
+		sb.WriteString("------------\n")
+		for _, result := range report.Results {
+			if result.Flaky {
+				sb.WriteString(fmt.Sprintf("\n↻ %s\n", result.TargetURL))
+				sb.WriteString(fmt.Sprintf("  Source: %s\n", result.SourceURL))
+				sb.WriteString(fmt.Sprintf("  First:  %s\n", result.FirstCheck))
+				sb.WriteString(fmt.Sprintf("  Now:    %s\n", targetStatus(result.StatusCode, result.Status, "")))
+			}
+		}
+		sb.WriteString("\n")
+	}
+
 	// Warning links (redirects)
 	if report.WarningLinks > 0 {
 		sb.WriteString("Warnings (Redirects):\n")
@@ -92,6 +143,51 @@ func formatText(report *ValidationReport) string {
 		sb.WriteString("\n")
 	}
 
+	// Heavy assets
+	if report.OversizeLinks > 0 {
+		sb.WriteString("Heavy Assets:\n")
+		sb.WriteString("-------------\n")
+		for _, result := range report.Results {
+			if result.Oversize {
+				sb.WriteString(fmt.Sprintf("\n⚠ %s\n", result.TargetURL))
+				sb.WriteString(fmt.Sprintf("  Source: %s\n", result.SourceURL))
+				sb.WriteString(fmt.Sprintf("  Tag:    <%s>\n", result.Tag))
+				sb.WriteString(fmt.Sprintf("  Size:   %s (%s)\n", formatBytes(result.ContentLength), result.ContentType))
+			}
+		}
+		sb.WriteString("\n")
+	}
+
+	// Security findings
+	if report.Security != nil {
+		sb.WriteString(formatSecurityText(report.Security))
+	}
+
+	// On-page HTML/SEO checks
+	if report.PageChecks != nil {
+		sb.WriteString(formatPageChecksText(report.PageChecks))
+	}
+
+	// Link graph analysis
+	if report.LinkGraph != nil {
+		sb.WriteString(formatLinkGraphText(report.LinkGraph))
+	}
+
+	// TLS certificates
+	if report.TLS != nil {
+		sb.WriteString(formatTLSText(report.TLS))
+	}
+
+	// Sitemap audit
+	if report.SitemapAudit != nil {
+		sb.WriteString(formatSitemapAuditText(report.SitemapAudit))
+	}
+
+	// Hreflang validation
+	if report.Hreflang != nil {
+		sb.WriteString(formatHreflangText(report.Hreflang))
+	}
+
 	// Summary footer
 	if report.BrokenLinks == 0 {
 		sb.WriteString("✓ All links are valid!\n")
@@ -102,6 +198,250 @@ func formatText(report *ValidationReport) string {
 	return sb.String()
 }
 
+// formatBrokenTargetsText formats the broken-links-by-target section of the text report
+func formatBrokenTargetsText(targets []BrokenTarget) string {
+	var sb strings.Builder
+
+	sb.WriteString("Broken Links by Target:\n")
+	sb.WriteString("-----------------------\n")
+	for _, target := range targets {
+		sb.WriteString(fmt.Sprintf("\n✗ %s (%d occurrence(s) on %d page(s))\n", target.URL, len(target.Occurrences), target.Pages))
+		if target.Error != "" {
+			sb.WriteString(fmt.Sprintf("  Error:  %s\n", target.Error))
+		} else {
+			sb.WriteString(fmt.Sprintf("  Status: %d %s\n", target.StatusCode, target.Status))
+		}
+		for _, occurrence := range target.Occurrences {
+			line := fmt.Sprintf("  - %s <%s>", occurrence.SourceURL, occurrence.Tag)
+			if occurrence.LinkText != "" {
+				line += fmt.Sprintf(" %q", truncate(occurrence.LinkText, 60))
+			}
+			if occurrence.URL != target.URL {
+				line += fmt.Sprintf(" as %s", occurrence.URL)
+			}
+			sb.WriteString(line + "\n")
+		}
+	}
+	sb.WriteString("\n")
+
+	return sb.String()
+}
+
+// formatSitemapAuditText formats the sitemap audit section of the text report
+func formatSitemapAuditText(audit *SitemapAudit) string {
+	var sb strings.Builder
+
+	sb.WriteString("Sitemap Audit:\n")
+	sb.WriteString("--------------\n")
+	sb.WriteString(fmt.Sprintf("URLs Audited:      %d\n", audit.URLsAudited))
+	sb.WriteString(fmt.Sprintf("Issues Found:      %d\n", len(audit.Findings)))
+
+	if len(audit.Findings) == 0 {
+		sb.WriteString("\n✓ No sitemap issues found\n\n")
+		return sb.String()
+	}
+
+	issues := make([]string, 0, len(audit.IssuesByType))
+	for issue := range audit.IssuesByType {
+		issues = append(issues, issue)
+	}
+	sort.Strings(issues)
+	for _, issue := range issues {
+		sb.WriteString(fmt.Sprintf("  %s: %d\n", issue, audit.IssuesByType[issue]))
+	}
+
+	currentIssue := ""
+	for _, finding := range audit.Findings {
+		if finding.Issue != currentIssue {
+			currentIssue = finding.Issue
+			sb.WriteString(fmt.Sprintf("\n[%s]\n", currentIssue))
+		}
+		sb.WriteString(fmt.Sprintf("⚠ %s\n", finding.URL))
+		sb.WriteString(fmt.Sprintf("  %s\n", finding.Detail))
+		if finding.Sitemap != "" {
+			sb.WriteString(fmt.Sprintf("  Sitemap: %s\n", finding.Sitemap))
+		}
+	}
+	sb.WriteString("\n")
+
+	return sb.String()
+}
+
+// formatSecurityText formats the security section of the text report
+func formatSecurityText(security *SecurityReport) string {
+	var sb strings.Builder
+
+	sb.WriteString("Security:\n")
+	sb.WriteString("---------\n")
+	sb.WriteString(fmt.Sprintf("Issues Found:      %d\n", len(security.Findings)))
+
+	if len(security.Findings) == 0 {
+		sb.WriteString("\n✓ No mixed content or insecure links found\n\n")
+		return sb.String()
+	}
+
+	currentKind := ""
+	for _, finding := range security.Findings {
+		if finding.Kind != currentKind {
+			currentKind = finding.Kind
+			sb.WriteString(fmt.Sprintf("\n[%s] %d\n", currentKind, security.ByKind[currentKind]))
+		}
+		sb.WriteString(fmt.Sprintf("⚠ %s\n", finding.URL))
+		sb.WriteString(fmt.Sprintf("  Source: %s\n", finding.PageURL))
+		sb.WriteString(fmt.Sprintf("  %s\n", finding.Detail))
+	}
+	sb.WriteString("\n")
+
+	return sb.String()
+}
+
+// formatPageChecksText formats the on-page checks section of the text report
+func formatPageChecksText(checks *PageChecksReport) string {
+	var sb strings.Builder
+
+	sb.WriteString("Page Checks:\n")
+	sb.WriteString("------------\n")
+	sb.WriteString(fmt.Sprintf("Pages Checked:     %d\n", checks.PagesChecked))
+	sb.WriteString(fmt.Sprintf("Issues Found:      %d\n", len(checks.Findings)))
+
+	if len(checks.Findings) == 0 {
+		sb.WriteString("\n✓ No page structure issues found\n\n")
+		return sb.String()
+	}
+
+	currentIssue := ""
+	for _, finding := range checks.Findings {
+		if finding.Issue != currentIssue {
+			currentIssue = finding.Issue
+			sb.WriteString(fmt.Sprintf("\n[%s] %d\n", currentIssue, checks.IssuesByType[currentIssue]))
+		}
+		sb.WriteString(fmt.Sprintf("⚠ %s\n", finding.URL))
+		if finding.Element != "" {
+			sb.WriteString(fmt.Sprintf("  Element: %s\n", truncate(finding.Element, 100)))
+		}
+		sb.WriteString(fmt.Sprintf("  %s\n", finding.Detail))
+	}
+	sb.WriteString("\n")
+
+	return sb.String()
+}
+
+// formatLinkGraphText formats the link graph section of the text report
+func formatLinkGraphText(graph *LinkGraph) string {
+	var sb strings.Builder
+
+	sb.WriteString("Link Graph:\n")
+	sb.WriteString("-----------\n")
+	sb.WriteString(fmt.Sprintf("Root:              %s\n", graph.Root))
+	sb.WriteString(fmt.Sprintf("Pages:             %d\n", len(graph.Nodes)))
+	sb.WriteString(fmt.Sprintf("Internal Links:    %d\n", len(graph.Edges)))
+	sb.WriteString(fmt.Sprintf("Orphans:           %d\n", len(graph.Orphans)))
+	sb.WriteString(fmt.Sprintf("Not in Sitemap:    %d\n", len(graph.NotInSitemap)))
+	sb.WriteString(fmt.Sprintf("Unreachable:       %d\n", len(graph.Unreachable)))
+
+	depths := make([]int, 0, len(graph.DepthCounts))
+	for depth := range graph.DepthCounts {
+		depths = append(depths, depth)
+	}
+	sort.Ints(depths)
+	if len(depths) > 0 {
+		sb.WriteString("\nClick Depth:\n")
+		for _, depth := range depths {
+			sb.WriteString(fmt.Sprintf("  %d: %d page(s)\n", depth, graph.DepthCounts[depth]))
+		}
+	}
+
+	writeList := func(title, hint string, urls []string) {
+		if len(urls) == 0 {
+			return
+		}
+		sb.WriteString(fmt.Sprintf("\n[%s] %d - %s\n", title, len(urls), hint))
+		for _, u := range urls {
+			sb.WriteString(fmt.Sprintf("⚠ %s\n", u))
+		}
+	}
+	writeList("orphan", "in the sitemap but no crawled page links here", graph.Orphans)
+	writeList("not-in-sitemap", "linked internally but missing from the sitemap", graph.NotInSitemap)
+	writeList("unreachable", "linked, but only from pages the root doesn't reach", graph.Unreachable)
+	sb.WriteString("\n")
+
+	return sb.String()
+}
+
+// formatTLSText formats the per-host TLS summary of the text report
+func formatTLSText(tlsReport *TLSReport) string {
+	var sb strings.Builder
+
+	sb.WriteString("TLS Certificates:\n")
+	sb.WriteString("-----------------\n")
+	sb.WriteString(fmt.Sprintf("Hosts Inspected:   %d\n", len(tlsReport.Hosts)))
+	sb.WriteString(fmt.Sprintf("Issues Found:      %d\n", len(tlsReport.Findings)))
+
+	now := time.Now()
+	for _, host := range tlsReport.Hosts {
+		if host.Error != "" {
+			sb.WriteString(fmt.Sprintf("\n✗ %s\n", host.Host))
+			sb.WriteString(fmt.Sprintf("  Error:   %s\n", host.Error))
+			continue
+		}
+
+		marker := "✓"
+		if !host.Verified || host.DaysUntilExpiry(now) < tlsReport.ExpiryDays {
+			marker = "⚠"
+		}
+		sb.WriteString(fmt.Sprintf("\n%s %s\n", marker, host.Host))
+		sb.WriteString(fmt.Sprintf("  Issuer:  %s\n", host.Issuer))
+		sb.WriteString(fmt.Sprintf("  Expires: %s (%d days)\n", host.NotAfter.Format("2006-01-02"), host.DaysUntilExpiry(now)))
+		sb.WriteString(fmt.Sprintf("  Version: %s\n", host.Version))
+	}
+
+	if len(tlsReport.Findings) == 0 {
+		sb.WriteString("\n✓ No certificate problems found\n\n")
+		return sb.String()
+	}
+
+	sb.WriteString("\n")
+	for _, finding := range tlsReport.Findings {
+		sb.WriteString(fmt.Sprintf("[%s] %s\n", finding.Kind, finding.Host))
+		sb.WriteString(fmt.Sprintf("  %s\n", finding.Detail))
+	}
+	sb.WriteString("\n")
+
+	return sb.String()
+}
+
+// formatHreflangText formats the hreflang section of the text report
+func formatHreflangText(hreflang *HreflangReport) string {
+	var sb strings.Builder
+
+	sb.WriteString("Hreflang:\n")
+	sb.WriteString("---------\n")
+	sb.WriteString(fmt.Sprintf("Pages Checked:     %d\n", hreflang.PagesChecked))
+	sb.WriteString(fmt.Sprintf("Alternates:        %d\n", hreflang.AlternatesChecked))
+	sb.WriteString(fmt.Sprintf("Issues Found:      %d\n", len(hreflang.Findings)))
+
+	if len(hreflang.Findings) == 0 {
+		sb.WriteString("\n✓ No hreflang issues found\n\n")
+		return sb.String()
+	}
+
+	currentIssue := ""
+	for _, finding := range hreflang.Findings {
+		if finding.Issue != currentIssue {
+			currentIssue = finding.Issue
+			sb.WriteString(fmt.Sprintf("\n[%s] %d\n", currentIssue, hreflang.IssuesByType[currentIssue]))
+		}
+		sb.WriteString(fmt.Sprintf("⚠ %s\n", finding.URL))
+		if finding.Alternate != "" {
+			sb.WriteString(fmt.Sprintf("  Alternate: %s (%s)\n", finding.Alternate, finding.Hreflang))
+		}
+		sb.WriteString(fmt.Sprintf("  %s\n", finding.Detail))
+	}
+	sb.WriteString("\n")
+
+	return sb.String()
+}
+
 // formatJSON formats the report as JSON
 func formatJSON(report *ValidationReport) (string, error) {
 	data, err := json.MarshalIndent(report, "", "  ")
@@ -117,7 +457,7 @@ func formatCSV(report *ValidationReport) (string, error) {
 	writer := csv.NewWriter(&sb)
 
 	// Header
-	header := []string{"Source URL", "Target URL", "Status Code", "Status", "Is Broken", "Is External", "Tag", "Link Text", "Error", "Duration (ms)"}
+	header := []string{"Source URL", "Target URL", "Status Code", "Status", "Is Broken", "Is External", "Tag", "Link Text", "Error", "Duration (ms)", "Soft 404", "Content Type", "Content Length", "Oversize", "Category"}
 	if err := writer.Write(header); err != nil {
 		return "", err
 	}
@@ -140,12 +480,186 @@ func formatCSV(report *ValidationReport) (string, error) {
 			result.LinkText,
 			errorStr,
 			fmt.Sprintf("%d", result.Duration.Milliseconds()),
+			fmt.Sprintf("%t", result.Soft404),
+			result.ContentType,
+			fmt.Sprintf("%d", result.ContentLength),
+			fmt.Sprintf("%t", result.Oversize),
+			"link",
+		}
+		if err := writer.Write(row); err != nil {
+			return "", err
+		}
+	}
+
+	// Every occurrence of each broken target, grouped and sorted by occurrence count
+	for _, target := range report.BrokenTargets {
+		for _, occurrence := range target.Occurrences {
+			row := []string{
+				occurrence.SourceURL,
+				occurrence.URL,
+				fmt.Sprintf("%d", target.StatusCode),
+				target.Status,
+				"", // Already counted by the link rows; Category marks these
+				"",
+				occurrence.Tag,
+				occurrence.LinkText,
+				target.Error,
+				"",
+				"",
+				"",
+				"",
+				"",
+				"broken-target",
+			}
+			if err := writer.Write(row); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	// Flaky links repeat with what the first check saw as the error
+	for _, result := range report.Results {
+		if !result.Flaky {
+			continue
+		}
+		row := []string{
+			result.SourceURL,
+			result.TargetURL,
+			fmt.Sprintf("%d", result.StatusCode),
+			result.Status,
+			"",
+			fmt.Sprintf("%t", result.IsExternal),
+			result.Tag,
+			result.LinkText,
+			result.FirstCheck,
+			fmt.Sprintf("%d", result.Duration.Milliseconds()),
+			"false",
+			result.ContentType,
+			fmt.Sprintf("%d", result.ContentLength),
+			"false",
+			"flaky",
 		}
 		if err := writer.Write(row); err != nil {
 			return "", err
 		}
 	}
 
+	// Security findings share the table, distinguished by the Category column
+	// Findings aren't link checks, so the Is Broken, Soft 404 and Oversize
+	// columns stay empty for them and the rows below
+	if report.Security != nil {
+		for _, finding := range report.Security.Findings {
+			row := []string{
+				finding.PageURL,
+				finding.URL,
+				"",
+				"Security: " + finding.Kind,
+				"",
+				"",
+				finding.Tag,
+				"",
+				finding.Detail,
+				"",
+				"",
+				"",
+				"",
+				"",
+				"security",
+			}
+			if err := writer.Write(row); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	// Page findings describe the page itself, so the element goes in the target column
+	if report.PageChecks != nil {
+		for _, finding := range report.PageChecks.Findings {
+			row := []string{
+				finding.URL,
+				finding.Element,
+				"",
+				"Page: " + finding.Issue,
+				"",
+				"",
+				"",
+				"",
+				finding.Detail,
+				"",
+				"",
+				"",
+				"",
+				"",
+				"page",
+			}
+			if err := writer.Write(row); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	// Link graph findings are per page
+	if report.LinkGraph != nil {
+		graphRows := []struct {
+			kind string
+			urls []string
+		}{
+			{"orphan", report.LinkGraph.Orphans},
+			{"not-in-sitemap", report.LinkGraph.NotInSitemap},
+			{"unreachable", report.LinkGraph.Unreachable},
+		}
+		for _, group := range graphRows {
+			for _, pageURL := range group.urls {
+				row := []string{
+					"",
+					pageURL,
+					"",
+					"Graph: " + group.kind,
+					"",
+					"false",
+					"a",
+					"",
+					"",
+					"",
+					"",
+					"",
+					"",
+					"",
+					"graph",
+				}
+				if err := writer.Write(row); err != nil {
+					return "", err
+				}
+			}
+		}
+	}
+
+	// TLS findings are per host, so only the target column is filled
+	if report.TLS != nil {
+		for _, finding := range report.TLS.Findings {
+			row := []string{
+				"",
+				finding.Host,
+				"",
+				"TLS: " + finding.Kind,
+				"",
+				"",
+				"",
+				"",
+				finding.Detail,
+				"",
+				"",
+				"",
+				"",
+				"",
+				"tls",
+			}
+			if err := writer.Write(row); err != nil {
+				return "", err
+			}
+		}
+	}
+
 	writer.Flush()
 	if err := writer.Error(); err != nil {
 		return "", err
@@ -154,16 +668,6 @@ func formatCSV(report *ValidationReport) (string, error) {
 	return sb.String(), nil
 }
 
-// WriteReportToFile writes the report to a file
-func WriteReportToFile(report *ValidationReport, format, filename string) error {
-	content, err := FormatReport(report, format)
-	if err != nil {
-		return err
-	}
-
-	return os.WriteFile(filename, []byte(content), 0644)
-}
-
 // Helper functions
 
 func percentage(part, total int) float64 {
@@ -0,0 +1,199 @@
+package validator
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	"linkchex/internal/fetcher"
+)
+
+// Security finding kinds
+const (
+	SecurityMixedActive        = "mixed-active"             // Script/stylesheet loaded over http on an https page
+	SecurityMixedPassive       = "mixed-passive"            // Image/media loaded over http on an https page
+	SecurityInsecureLink       = "insecure-link"            // http:// link to a host that also serves https
+	SecurityInsecureUnverified = "insecure-link-unverified" // http:// link to an external host that wasn't probed
+	SecurityDowngrade          = "https-downgrade"          // Redirect chain goes from https to http
+)
+
+// SecurityFinding is a single insecure link or resource
+type SecurityFinding struct {
+	PageURL string // Page the link was found on
+	URL     string
+	Tag     string
+	Kind    string // One of the Security* constants
+	Detail  string
+}
+
+// SecurityReport collects security findings across all pages
+type SecurityReport struct {
+	Findings    []SecurityFinding
+	ByKind      map[string]int
+	HTTPSProbes map[string]bool // Whether each probed host (without port) also serves https
+}
+
+// securityAuditor checks extracted links for mixed content and insecure URLs
+type securityAuditor struct {
+	client   *fetcher.Client
+	probes   map[string]*httpsProbe
+	findings []SecurityFinding
+	seen     map[string]bool // Dedupes page+URL+kind
+	mu       sync.Mutex
+}
+
+// httpsProbe records whether a host answers over https, probed once
+type httpsProbe struct {
+	once      sync.Once
+	supported bool
+}
+
+// SetSecurityAudit enables mixed content, insecure link and https downgrade checks
+func (v *Validator) SetSecurityAudit(enabled bool) {
+	if !enabled {
+		v.security = nil
+		return
+	}
+	v.security = &securityAuditor{
+		client: v.client,
+		probes: make(map[string]*httpsProbe),
+		seen:   make(map[string]bool),
+	}
+}
+
+// auditPage checks every link extracted from a page, including ones that
+// won't be validated (e.g. external links when --check-external is off)
+// Only hosts that are validated anyway get an https probe; other http links
+// are reported without one, so no requests go to unchecked third parties
+func (a *securityAuditor) auditPage(pageURL string, links []fetcher.Link, checkExternal bool) {
+	page, err := url.Parse(pageURL)
+	if err != nil {
+		return
+	}
+
+	for _, link := range links {
+		target, err := url.Parse(link.URL)
+		if err != nil || target.Scheme != "http" {
+			continue
+		}
+
+		if page.Scheme == "https" {
+			switch mixedContentKind(link) {
+			case SecurityMixedActive:
+				a.add(SecurityFinding{PageURL: pageURL, URL: link.URL, Tag: link.Tag, Kind: SecurityMixedActive,
+					Detail: fmt.Sprintf("<%s> loads over http on an https page and will be blocked by browsers", link.Tag)})
+			case SecurityMixedPassive:
+				a.add(SecurityFinding{PageURL: pageURL, URL: link.URL, Tag: link.Tag, Kind: SecurityMixedPassive,
+					Detail: fmt.Sprintf("<%s> loads over http on an https page", link.Tag)})
+			}
+		}
+
+		// https is served on its default port, not the http link's explicit one
+		host := strings.TrimSuffix(target.Host, ":"+target.Port())
+		if link.IsExternal && !checkExternal {
+			a.add(SecurityFinding{PageURL: pageURL, URL: link.URL, Tag: link.Tag, Kind: SecurityInsecureUnverified,
+				Detail: fmt.Sprintf("http link to external host %s; https support not probed since external links aren't checked", target.Host)})
+		} else if a.supportsHTTPS(host) {
+			a.add(SecurityFinding{PageURL: pageURL, URL: link.URL, Tag: link.Tag, Kind: SecurityInsecureLink,
+				Detail: fmt.Sprintf("%s also serves https; link to https://%s%s instead", host, host, target.RequestURI())})
+		}
+	}
+}
+
+// auditRedirects flags redirect chains that downgrade from https to http
+func (a *securityAuditor) auditRedirects(pageURL string, link fetcher.Link, resp *fetcher.Response) {
+	secure := false
+	for _, hop := range resp.Redirects {
+		if strings.HasPrefix(hop, "https://") {
+			secure = true
+		} else if secure && strings.HasPrefix(hop, "http://") {
+			a.add(SecurityFinding{PageURL: pageURL, URL: link.URL, Tag: link.Tag, Kind: SecurityDowngrade,
+				Detail: fmt.Sprintf("redirect chain downgrades to %s", hop)})
+			return
+		}
+	}
+}
+
+// supportsHTTPS probes whether a host (without port) answers over https,
+// once per host
+func (a *securityAuditor) supportsHTTPS(host string) bool {
+	a.mu.Lock()
+	probe, ok := a.probes[host]
+	if !ok {
+		probe = &httpsProbe{}
+		a.probes[host] = probe
+	}
+	a.mu.Unlock()
+
+	probe.once.Do(func() {
+		resp := a.client.Head("https://" + host + "/")
+		probe.supported = resp.Error == nil
+	})
+	return probe.supported
+}
+
+// add records a finding unless it was already reported
+func (a *securityAuditor) add(finding SecurityFinding) {
+	key := finding.PageURL + "\x00" + finding.URL + "\x00" + finding.Kind
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.seen[key] {
+		return
+	}
+	a.seen[key] = true
+	a.findings = append(a.findings, finding)
+}
+
+// report builds the security section of the validation report
+func (a *securityAuditor) report() *SecurityReport {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	report := &SecurityReport{
+		Findings:    append([]SecurityFinding(nil), a.findings...),
+		ByKind:      make(map[string]int),
+		HTTPSProbes: make(map[string]bool),
+	}
+	for _, finding := range report.Findings {
+		report.ByKind[finding.Kind]++
+	}
+	for host, probe := range a.probes {
+		report.HTTPSProbes[host] = probe.supported
+	}
+
+	sort.SliceStable(report.Findings, func(i, j int) bool {
+		x, y := report.Findings[i], report.Findings[j]
+		if x.Kind != y.Kind {
+			return x.Kind < y.Kind
+		}
+		if x.PageURL != y.PageURL {
+			return x.PageURL < y.PageURL
+		}
+		return x.URL < y.URL
+	})
+
+	return report
+}
+
+// mixedContentKind classifies a link as active or passive mixed content
+// Navigational <a> links are not mixed content
+func mixedContentKind(link fetcher.Link) string {
+	switch link.Tag {
+	case "script":
+		return SecurityMixedActive
+	case "img":
+		return SecurityMixedPassive
+	case "link":
+		// Only <link> relations that load a resource; canonical/alternate are references
+		if link.HasRel("stylesheet") || link.HasRel("preload") || link.HasRel("modulepreload") {
+			return SecurityMixedActive
+		}
+		if link.HasRel("icon") {
+			return SecurityMixedPassive
+		}
+	}
+	return ""
+}
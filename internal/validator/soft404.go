@@ -0,0 +1,168 @@
+package validator
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"linkchex/internal/fetcher"
+)
+
+// soft404Similarity is the word-set overlap above which a page is considered
+// the same as the host's "not found" page
+const soft404Similarity = 0.9
+
+// DefaultSoft404TitlePattern matches titles that are nothing but a "not found"
+// message, optionally followed by a site name after a separator
+// ("404 - Page Not Found | Example"), but not titles that merely mention one
+// ("Error 404 explained")
+const DefaultSoft404TitlePattern = `(?i)^\s*(error\s+)?(404|page not found|not found)(\s*[-–—|].*)?\s*$`
+
+// softNotFoundDetector detects pages that return 200 but are really error pages
+type softNotFoundDetector struct {
+	client       *fetcher.Client
+	titlePattern *regexp.Regexp // nil to skip title matching
+	bodyPattern  *regexp.Regexp // nil to skip body matching
+	fingerprints map[string]*hostFingerprint
+	mu           sync.Mutex
+}
+
+// hostFingerprint is the response of a known-nonexistent URL on a host
+type hostFingerprint struct {
+	once     sync.Once
+	soft     bool            // Whether the host answers missing URLs with 2xx
+	finalURL string          // Where the missing URL ended up after redirects
+	words    map[string]bool // Word set of the error page's text
+}
+
+// SetSoft404Detection enables soft 404 detection for <a> links that return 2xx
+// Pages are compared against a fingerprint of a nonexistent URL on the same
+// host, and matched against optional title and body regular expressions
+func (v *Validator) SetSoft404Detection(titlePattern, bodyPattern string) error {
+	detector := &softNotFoundDetector{
+		client:       v.client,
+		fingerprints: make(map[string]*hostFingerprint),
+	}
+
+	if titlePattern != "" {
+		re, err := regexp.Compile(titlePattern)
+		if err != nil {
+			return fmt.Errorf("invalid soft 404 title pattern: %w", err)
+		}
+		detector.titlePattern = re
+	}
+	if bodyPattern != "" {
+		re, err := regexp.Compile(bodyPattern)
+		if err != nil {
+			return fmt.Errorf("invalid soft 404 body pattern: %w", err)
+		}
+		detector.bodyPattern = re
+	}
+
+	v.soft404 = detector
+	return nil
+}
+
+// check fetches a page and returns why it looks like a soft 404 ("" if it doesn't)
+func (d *softNotFoundDetector) check(targetURL string) string {
+	resp := d.client.Get(targetURL)
+	if resp.Error != nil || resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return ""
+	}
+	if !isHTML(resp.Header.Get("Content-Type")) {
+		return ""
+	}
+
+	title, text, err := fetcher.ExtractPageText(resp.Body)
+	if err != nil {
+		return ""
+	}
+
+	if d.titlePattern != nil && d.titlePattern.MatchString(title) {
+		return fmt.Sprintf("title %q matches not-found pattern", truncate(title, 60))
+	}
+	if d.bodyPattern != nil && d.bodyPattern.MatchString(text) {
+		return "body matches not-found pattern"
+	}
+
+	parsed, err := url.Parse(targetURL)
+	if err != nil {
+		return ""
+	}
+	fp := d.fingerprint(parsed)
+	if !fp.soft {
+		return ""
+	}
+
+	if resp.FinalURL == fp.finalURL && resp.FinalURL != targetURL {
+		return fmt.Sprintf("redirects to the host's not-found page %s", fp.finalURL)
+	}
+	if similarity(fp.words, wordSet(text)) >= soft404Similarity {
+		return "content matches the host's not-found page"
+	}
+	return ""
+}
+
+// fingerprint fetches a known-nonexistent URL on the target's host, once per host
+func (d *softNotFoundDetector) fingerprint(target *url.URL) *hostFingerprint {
+	origin := target.Scheme + "://" + target.Host
+
+	d.mu.Lock()
+	fp, ok := d.fingerprints[origin]
+	if !ok {
+		fp = &hostFingerprint{}
+		d.fingerprints[origin] = fp
+	}
+	d.mu.Unlock()
+
+	fp.once.Do(func() {
+		probeURL := fmt.Sprintf("%s/linkchex-not-found-%d", origin, time.Now().UnixNano())
+		resp := d.client.Get(probeURL)
+		if resp.Error != nil || resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return // Host returns proper errors for missing pages
+		}
+
+		_, text, err := fetcher.ExtractPageText(resp.Body)
+		if err != nil {
+			return
+		}
+		fp.soft = true
+		fp.finalURL = resp.FinalURL
+		fp.words = wordSet(text)
+	})
+
+	return fp
+}
+
+// wordSet returns the set of lowercased words in text
+func wordSet(text string) map[string]bool {
+	words := make(map[string]bool)
+	for _, word := range strings.Fields(strings.ToLower(text)) {
+		words[word] = true
+	}
+	return words
+}
+
+// similarity returns the Jaccard index of two word sets
+func similarity(a, b map[string]bool) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 1
+	}
+
+	shared := 0
+	for word := range a {
+		if b[word] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+// isHTML reports whether a Content-Type header denotes an HTML document
+func isHTML(contentType string) bool {
+	contentType = strings.ToLower(contentType)
+	return contentType == "" || strings.HasPrefix(contentType, "text/html") || strings.HasPrefix(contentType, "application/xhtml")
+}
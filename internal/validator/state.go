@@ -0,0 +1,161 @@
+package validator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"linkchex/internal/fetcher"
+)
+
+// State persists crawl results between runs for incremental validation
+type State struct {
+	Pages map[string]*PageState `json:"pages"` // Keyed by page URL
+	Links map[string]*LinkState `json:"links"` // Keyed by canonical target URL
+	mu    sync.Mutex
+}
+
+// PageState records when a page was last crawled and what it linked to
+type PageState struct {
+	CrawledAt  time.Time              `json:"crawled_at"`
+	StatusCode int                    `json:"status_code"`
+	FinalURL   string                 `json:"final_url,omitempty"`
+	Canonical  string                 `json:"canonical,omitempty"`
+	NoIndex    bool                   `json:"noindex,omitempty"`
+	Alternates []fetcher.Alternate    `json:"alternates,omitempty"`
+	Structure  *fetcher.PageStructure `json:"structure,omitempty"` // Only recorded when page checks are enabled
+	Links      []fetcher.Link         `json:"links"`
+}
+
+// LinkState records the last validation result for a link target
+type LinkState struct {
+	CheckedAt     time.Time `json:"checked_at"`
+	StatusCode    int       `json:"status_code"`
+	Status        string    `json:"status"`
+	Error         string    `json:"error,omitempty"`
+	IsBroken      bool      `json:"is_broken"`
+	Soft404       bool      `json:"soft_404,omitempty"`
+	ContentType   string    `json:"content_type,omitempty"`
+	ContentLength int64     `json:"content_length,omitempty"`
+	Oversize      bool      `json:"oversize,omitempty"`
+	FinalURL      string    `json:"final_url,omitempty"` // After redirects
+}
+
+// NewState creates an empty state
+func NewState() *State {
+	return &State{
+		Pages: make(map[string]*PageState),
+		Links: make(map[string]*LinkState),
+	}
+}
+
+// LoadState reads a state file, returning an empty state if it doesn't exist yet
+func LoadState(path string) (*State, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return NewState(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state file: %w", err)
+	}
+
+	state := NewState()
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("failed to parse state file: %w", err)
+	}
+	if state.Pages == nil {
+		state.Pages = make(map[string]*PageState)
+	}
+	if state.Links == nil {
+		state.Links = make(map[string]*LinkState)
+	}
+	return state, nil
+}
+
+// Save writes the state to a file
+func (s *State) Save(path string) error {
+	s.mu.Lock()
+	data, err := json.MarshalIndent(s, "", "  ")
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(path, data)
+}
+
+// writeFileAtomic writes data to a temporary file and renames it over path,
+// so a crash mid-write can't leave a truncated file behind
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(0644); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// page returns the stored state for a page, if any
+func (s *State) page(pageURL string) (*PageState, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	page, ok := s.Pages[pageURL]
+	return page, ok
+}
+
+// setPage stores the state for a page
+func (s *State) setPage(pageURL string, page *PageState) {
+	s.mu.Lock()
+	s.Pages[pageURL] = page
+	s.mu.Unlock()
+}
+
+// link returns the stored result for a link target if it is younger than ttl
+// Broken results are never reused, so a fixed link clears on the next run
+func (s *State) link(targetURL string, ttl time.Duration) (*LinkState, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	link, ok := s.Links[targetURL]
+	if !ok || link.IsBroken || time.Since(link.CheckedAt) > ttl {
+		return nil, false
+	}
+	return link, true
+}
+
+// setLink stores the validation result for a link target
+func (s *State) setLink(targetURL string, result Result) {
+	link := &LinkState{
+		CheckedAt:     time.Now(),
+		StatusCode:    result.StatusCode,
+		Status:        result.Status,
+		IsBroken:      result.IsBroken,
+		Soft404:       result.Soft404,
+		ContentType:   result.ContentType,
+		ContentLength: result.ContentLength,
+		Oversize:      result.Oversize,
+		FinalURL:      result.finalURL,
+	}
+	if result.Error != nil {
+		link.Error = result.Error.Error()
+	}
+
+	s.mu.Lock()
+	s.Links[targetURL] = link
+	s.mu.Unlock()
+}
@@ -0,0 +1,270 @@
+package validator
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// ResultGroup is a set of results sharing one key, as returned by groupBy
+type ResultGroup struct {
+	Key     string
+	Results []Result
+}
+
+// GraphFinding is an orphan, not-in-sitemap or unreachable page
+type GraphFinding struct {
+	Kind string
+	GraphNode
+}
+
+// RenderTemplate renders the report through a user-supplied Go template.
+// Files named *.html, *.htm, *.html.tmpl or *.htm.tmpl use html/template,
+// which escapes output and can redefine blocks of the built-in HTML report;
+// anything else uses text/template
+func RenderTemplate(report *ValidationReport, filename string) (string, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return "", fmt.Errorf("failed to read template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	name := filepath.Base(filename)
+	if isHTMLTemplate(filename) {
+		// Parse into the built-in set so {{template "report.html.tmpl" .}}
+		// renders the default report with any blocks redefined here
+		tmpl, err := builtinHTMLTemplates()
+		if err != nil {
+			return "", err
+		}
+		tmpl, err = tmpl.New(name).Parse(string(data))
+		if err != nil {
+			return "", fmt.Errorf("failed to parse template: %w", err)
+		}
+		if err := tmpl.Execute(&buf, report); err != nil {
+			return "", fmt.Errorf("failed to render template: %w", err)
+		}
+		return buf.String(), nil
+	}
+
+	tmpl, err := template.New(name).Funcs(templateFuncs()).Parse(string(data))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse template: %w", err)
+	}
+	if err := tmpl.Execute(&buf, report); err != nil {
+		return "", fmt.Errorf("failed to render template: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// isHTMLTemplate reports whether a template file produces HTML
+func isHTMLTemplate(filename string) bool {
+	name := strings.ToLower(filename)
+	for _, suffix := range []string{".tmpl", ".tpl", ".gotmpl"} {
+		name = strings.TrimSuffix(name, suffix)
+	}
+	ext := filepath.Ext(name)
+	return ext == ".html" || ext == ".htm"
+}
+
+// templateFuncs returns the helper functions available to report templates
+// Functions taking a value and an option put the value last so they can be
+// used in pipelines, e.g. {{.URL | truncate 80}}
+func templateFuncs() template.FuncMap {
+	return template.FuncMap{
+		// Formatting
+		"percent": func(part, total int) string {
+			return fmt.Sprintf("%.1f%%", percentage(part, total))
+		},
+		"truncate": func(maxLen int, s string) string {
+			return truncate(s, maxLen)
+		},
+		"duration": func(d time.Duration) time.Duration {
+			return d.Round(time.Millisecond)
+		},
+		"lower":     strings.ToLower,
+		"upper":     strings.ToUpper,
+		"join":      func(sep string, items []string) string { return strings.Join(items, sep) },
+		"errorText": errorText,
+		"depth":     depthLabel,
+		"now":       time.Now,
+
+		// Result helpers
+		"statusLabel": statusLabel,
+		"statusClass": statusClass,
+		"linkType": func(result Result) string {
+			if result.IsExternal {
+				return "external"
+			}
+			return "internal"
+		},
+		"broken": func(results []Result) []Result {
+			return filterResults(results, func(r Result) bool { return r.IsBroken })
+		},
+		"flaky": func(results []Result) []Result {
+			return filterResults(results, func(r Result) bool { return r.Flaky })
+		},
+		"redirects": func(results []Result) []Result {
+			return filterResults(results, func(r Result) bool { return r.StatusCode >= 300 && r.StatusCode < 400 })
+		},
+		"external": func(results []Result) []Result {
+			return filterResults(results, func(r Result) bool { return r.IsExternal })
+		},
+		"internal": func(results []Result) []Result {
+			return filterResults(results, func(r Result) bool { return !r.IsExternal })
+		},
+		"groupBy": groupBy,
+
+		// Section helpers
+		"graphFindings": graphFindings,
+		"tlsProblems":   tlsProblems,
+
+		// Built-in HTML report assets, for custom HTML templates
+		"reportCSS": reportCSS,
+		"reportJS":  reportJS,
+	}
+}
+
+// errorText returns an error's message, or "" for nil
+func errorText(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+// statusLabel summarizes a result as Broken, Soft 404, Flaky, Redirect or Success
+func statusLabel(result Result) string {
+	switch {
+	case result.Soft404:
+		return "Soft 404"
+	case result.IsBroken:
+		return "Broken"
+	case result.Flaky:
+		return "Flaky"
+	case result.StatusCode >= 300 && result.StatusCode < 400:
+		return "Redirect"
+	default:
+		return "Success"
+	}
+}
+
+// statusClass returns the badge class suffix for a result: error, warning or success
+func statusClass(result Result) string {
+	switch statusLabel(result) {
+	case "Soft 404", "Broken":
+		return "error"
+	case "Flaky", "Redirect":
+		return "warning"
+	default:
+		return "success"
+	}
+}
+
+// filterResults returns the results matching keep
+func filterResults(results []Result, keep func(Result) bool) []Result {
+	var filtered []Result
+	for _, result := range results {
+		if keep(result) {
+			filtered = append(filtered, result)
+		}
+	}
+	return filtered
+}
+
+// groupBy groups results by source, target, host, tag, status, code or type,
+// largest group first
+func groupBy(field string, results []Result) ([]ResultGroup, error) {
+	var key func(Result) string
+	switch field {
+	case "source":
+		key = func(r Result) string { return r.SourceURL }
+	case "target":
+		key = func(r Result) string { return r.TargetURL }
+	case "host":
+		key = func(r Result) string {
+			if parsed, err := url.Parse(r.TargetURL); err == nil && parsed.Host != "" {
+				return parsed.Host
+			}
+			return r.TargetURL
+		}
+	case "tag":
+		key = func(r Result) string { return r.Tag }
+	case "status":
+		key = statusLabel
+	case "code":
+		key = func(r Result) string { return fmt.Sprintf("%d", r.StatusCode) }
+	case "type":
+		key = func(r Result) string {
+			if r.IsExternal {
+				return "external"
+			}
+			return "internal"
+		}
+	default:
+		return nil, fmt.Errorf("unknown groupBy field %q (use source, target, host, tag, status, code or type)", field)
+	}
+
+	index := make(map[string]int)
+	var groups []ResultGroup
+	for _, result := range results {
+		k := key(result)
+		i, ok := index[k]
+		if !ok {
+			i = len(groups)
+			index[k] = i
+			groups = append(groups, ResultGroup{Key: k})
+		}
+		groups[i].Results = append(groups[i].Results, result)
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		return len(groups[i].Results) > len(groups[j].Results)
+	})
+	return groups, nil
+}
+
+// graphFindings lists orphan, not-in-sitemap and unreachable pages with their
+// graph nodes, in that order
+func graphFindings(graph *LinkGraph) []GraphFinding {
+	nodes := make(map[string]GraphNode, len(graph.Nodes))
+	for _, n := range graph.Nodes {
+		nodes[n.URL] = n
+	}
+
+	groups := []struct {
+		kind string
+		urls []string
+	}{
+		{"orphan", graph.Orphans},
+		{"not-in-sitemap", graph.NotInSitemap},
+		{"unreachable", graph.Unreachable},
+	}
+
+	var findings []GraphFinding
+	for _, group := range groups {
+		for _, pageURL := range group.urls {
+			n := nodes[pageURL]
+			n.URL = pageURL
+			findings = append(findings, GraphFinding{Kind: group.kind, GraphNode: n})
+		}
+	}
+	return findings
+}
+
+// tlsProblems returns the kinds of TLS findings for a host
+func tlsProblems(tlsReport *TLSReport, host string) []string {
+	var kinds []string
+	for _, finding := range tlsReport.Findings {
+		if finding.Host == host {
+			kinds = append(kinds, finding.Kind)
+		}
+	}
+	return kinds
+}
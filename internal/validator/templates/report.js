@@ -0,0 +1,170 @@
+// Table sorting and filtering
+const table = document.getElementById('resultsTable');
+const tbody = document.getElementById('resultsBody');
+const searchInput = document.getElementById('searchInput');
+const statusFilterBtns = document.querySelectorAll('.status-filter');
+const codeFilterBtns = document.querySelectorAll('.code-filter');
+const noResults = document.getElementById('noResults');
+
+let currentSort = { column: null, direction: 'asc' };
+let currentFilter = 'all';
+let currentCodeFilter = 'all';
+let searchTerm = '';
+
+// Sorting
+table.querySelectorAll('th[data-sort]').forEach(th => {
+    th.addEventListener('click', () => {
+        const column = th.dataset.sort;
+
+        if (currentSort.column === column) {
+            currentSort.direction = currentSort.direction === 'asc' ? 'desc' : 'asc';
+        } else {
+            currentSort.column = column;
+            currentSort.direction = 'asc';
+        }
+
+        // Update header classes
+        table.querySelectorAll('th').forEach(h => {
+            h.classList.remove('sort-asc', 'sort-desc');
+        });
+        th.classList.add('sort-' + currentSort.direction);
+
+        sortTable();
+    });
+});
+
+function sortTable() {
+    const rows = Array.from(tbody.querySelectorAll('tr'));
+
+    rows.sort((a, b) => {
+        let aVal, bVal;
+
+        switch(currentSort.column) {
+            case 'status':
+                aVal = a.querySelector('.status-badge').textContent;
+                bVal = b.querySelector('.status-badge').textContent;
+                break;
+            case 'target':
+                aVal = a.querySelectorAll('td')[1].textContent;
+                bVal = b.querySelectorAll('td')[1].textContent;
+                break;
+            case 'source':
+                aVal = a.querySelectorAll('td')[2].textContent;
+                bVal = b.querySelectorAll('td')[2].textContent;
+                break;
+            case 'tag':
+                aVal = a.querySelectorAll('td')[3].textContent;
+                bVal = b.querySelectorAll('td')[3].textContent;
+                break;
+            case 'code':
+                aVal = a.querySelectorAll('td')[4].textContent;
+                bVal = b.querySelectorAll('td')[4].textContent;
+                break;
+            case 'type':
+                aVal = a.querySelectorAll('td')[5].textContent;
+                bVal = b.querySelectorAll('td')[5].textContent;
+                break;
+            case 'duration':
+                aVal = parseInt(a.querySelectorAll('td')[6].textContent);
+                bVal = parseInt(b.querySelectorAll('td')[6].textContent);
+                break;
+        }
+
+        if (currentSort.direction === 'asc') {
+            return aVal > bVal ? 1 : -1;
+        } else {
+            return aVal < bVal ? 1 : -1;
+        }
+    });
+
+    rows.forEach(row => tbody.appendChild(row));
+}
+
+// Status Filtering
+statusFilterBtns.forEach(btn => {
+    btn.addEventListener('click', () => {
+        statusFilterBtns.forEach(b => b.classList.remove('active'));
+        btn.classList.add('active');
+        currentFilter = btn.dataset.filter;
+        applyFilters();
+    });
+});
+
+// Status Code Filtering
+codeFilterBtns.forEach(btn => {
+    btn.addEventListener('click', () => {
+        codeFilterBtns.forEach(b => b.classList.remove('active'));
+        btn.classList.add('active');
+        currentCodeFilter = btn.dataset.codeFilter;
+        applyFilters();
+    });
+});
+
+// Search
+searchInput.addEventListener('input', (e) => {
+    searchTerm = e.target.value.toLowerCase();
+    applyFilters();
+});
+
+function applyFilters() {
+    const rows = tbody.querySelectorAll('tr');
+    let visibleCount = 0;
+
+    rows.forEach(row => {
+        let show = true;
+
+        // Filter by status/type
+        if (currentFilter !== 'all') {
+            const status = row.dataset.status.toLowerCase();
+            const type = row.dataset.type.toLowerCase();
+
+            if (currentFilter === 'broken' && status !== 'broken' && status !== 'soft 404') show = false;
+            if (currentFilter === 'success' && status !== 'success') show = false;
+            if (currentFilter === 'warning' && status !== 'redirect' && status !== 'flaky') show = false;
+            if (currentFilter === 'external' && type !== 'external') show = false;
+            if (currentFilter === 'internal' && type !== 'internal') show = false;
+        }
+
+        // Filter by status code
+        if (currentCodeFilter !== 'all' && show) {
+            const code = parseInt(row.dataset.code);
+
+            // Handle range filters
+            if (currentCodeFilter === '2xx' && (code < 200 || code >= 300)) {
+                show = false;
+            } else if (currentCodeFilter === '3xx' && (code < 300 || code >= 400)) {
+                show = false;
+            } else if (currentCodeFilter === '4xx' && (code < 400 || code >= 500)) {
+                show = false;
+            } else if (currentCodeFilter === '5xx' && (code < 500 || code >= 600)) {
+                show = false;
+            } else if (currentCodeFilter !== '2xx' && currentCodeFilter !== '3xx' &&
+                       currentCodeFilter !== '4xx' && currentCodeFilter !== '5xx') {
+                // Exact match for specific codes
+                if (code.toString() !== currentCodeFilter) {
+                    show = false;
+                }
+            }
+        }
+
+        // Filter by search term
+        if (searchTerm && show) {
+            const searchData = row.dataset.search;
+            if (!searchData.includes(searchTerm)) {
+                show = false;
+            }
+        }
+
+        row.style.display = show ? '' : 'none';
+        if (show) visibleCount++;
+    });
+
+    // Show/hide no results message
+    if (visibleCount === 0) {
+        table.style.display = 'none';
+        noResults.style.display = 'block';
+    } else {
+        table.style.display = 'table';
+        noResults.style.display = 'none';
+    }
+}
@@ -0,0 +1,134 @@
+package validator
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"linkchex/internal/fetcher"
+)
+
+// TLS finding kinds
+const (
+	TLSExpired          = "expired"
+	TLSExpiring         = "expiring"
+	TLSSelfSigned       = "self-signed"
+	TLSHostnameMismatch = "hostname-mismatch"
+	TLSUntrusted        = "untrusted"       // Chain doesn't verify for another reason
+	TLSLegacyProtocol   = "legacy-protocol" // TLS 1.0 or 1.1
+	TLSUnreachable      = "handshake-failed"
+)
+
+// TLSFinding is a single certificate problem on a host
+type TLSFinding struct {
+	Host   string
+	Kind   string // One of the TLS* constants
+	Detail string
+}
+
+// TLSReport summarizes the certificates of every https host contacted
+type TLSReport struct {
+	ExpiryDays int               // Certificates expiring within this many days are flagged
+	Hosts      []fetcher.TLSInfo // Per-host certificate details, sorted by host
+	Findings   []TLSFinding
+	ByKind     map[string]int
+}
+
+// SetTLSCheck records certificate details for every https host and flags
+// certificates that are invalid or expire within expiryDays
+func (v *Validator) SetTLSCheck(expiryDays int) {
+	v.tlsCheck = true
+	v.tlsExpiryDays = expiryDays
+	v.client.EnableTLSInspection()
+}
+
+// tlsReport builds the TLS section of the validation report
+func (v *Validator) tlsReport() *TLSReport {
+	report := &TLSReport{
+		ExpiryDays: v.tlsExpiryDays,
+		Hosts:      v.client.TLSHosts(),
+		ByKind:     make(map[string]int),
+	}
+
+	now := time.Now()
+	for i := range report.Hosts {
+		for _, finding := range tlsFindings(&report.Hosts[i], v.tlsExpiryDays, now) {
+			report.Findings = append(report.Findings, finding)
+			report.ByKind[finding.Kind]++
+		}
+	}
+
+	return report
+}
+
+// tlsFindings lists everything wrong with a host's certificate
+func tlsFindings(info *fetcher.TLSInfo, expiryDays int, now time.Time) []TLSFinding {
+	if info.Error != "" {
+		return []TLSFinding{{Host: info.Host, Kind: TLSUnreachable, Detail: info.Error}}
+	}
+
+	var findings []TLSFinding
+	add := func(kind, detail string) {
+		findings = append(findings, TLSFinding{Host: info.Host, Kind: kind, Detail: detail})
+	}
+
+	days := info.DaysUntilExpiry(now)
+	if now.After(info.NotAfter) {
+		add(TLSExpired, fmt.Sprintf("expired on %s", info.NotAfter.Format("2006-01-02")))
+	} else if days < expiryDays {
+		add(TLSExpiring, fmt.Sprintf("expires on %s (%d days)", info.NotAfter.Format("2006-01-02"), days))
+	}
+
+	if info.SelfSigned {
+		add(TLSSelfSigned, fmt.Sprintf("certificate is self-signed by %s", info.Issuer))
+	}
+	if !info.HostnameMatch {
+		add(TLSHostnameMismatch, fmt.Sprintf("certificate is for %s, not %s", strings.Join(certNames(info), ", "), hostname(info.Host)))
+	}
+	// Only report untrusted chains when nothing more specific explains it
+	if !info.Verified && len(findings) == 0 {
+		add(TLSUntrusted, info.VerifyError)
+	}
+
+	if info.Version == "TLS 1.0" || info.Version == "TLS 1.1" {
+		add(TLSLegacyProtocol, fmt.Sprintf("negotiated %s", info.Version))
+	}
+
+	return findings
+}
+
+// describeTLSError prefixes certificate failures with what was actually wrong,
+// instead of leaving only the opaque x509 error string
+func (v *Validator) describeTLSError(resp *fetcher.Response) error {
+	if resp.Error == nil || resp.TLS == nil {
+		return resp.Error
+	}
+	// Expiring-soon and protocol findings don't fail a handshake, so skip them
+	var causes []string
+	for _, finding := range tlsFindings(resp.TLS, v.tlsExpiryDays, time.Now()) {
+		if finding.Kind != TLSExpiring && finding.Kind != TLSLegacyProtocol {
+			causes = append(causes, finding.Detail)
+		}
+	}
+	if len(causes) == 0 {
+		return resp.Error
+	}
+	return fmt.Errorf("TLS certificate problem: %s: %w", strings.Join(causes, "; "), resp.Error)
+}
+
+// hostname strips the port from a host[:port]
+func hostname(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		return h
+	}
+	return host
+}
+
+// certNames returns the names a certificate is valid for
+func certNames(info *fetcher.TLSInfo) []string {
+	if len(info.DNSNames) > 0 {
+		return info.DNSNames
+	}
+	return []string{info.Subject}
+}
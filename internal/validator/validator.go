@@ -1,26 +1,37 @@
 package validator
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
-	"linkchex/internal/fetcher"
 	"github.com/schollz/progressbar/v3"
+	"linkchex/internal/fetcher"
+	"linkchex/internal/sitemap"
 )
 
 // Result represents the validation result for a single URL
 type Result struct {
-	SourceURL  string        // The page where the link was found
-	TargetURL  string        // The link being validated
-	StatusCode int           // HTTP status code
-	Status     string        // Status text
-	Error      error         // Error if validation failed
-	IsExternal bool          // Whether the link is external
-	Tag        string        // HTML tag (a, img, link, script)
-	LinkText   string        // Text content of the link (for <a> tags)
-	Duration   time.Duration // Time taken to validate
-	IsBroken   bool          // Whether the link is broken
+	SourceURL     string        // The page where the link was found
+	TargetURL     string        // The link being validated
+	StatusCode    int           // HTTP status code
+	Status        string        // Status text
+	Error         error         // Error if validation failed
+	IsExternal    bool          // Whether the link is external
+	Tag           string        // HTML tag (a, img, link, script)
+	LinkText      string        // Text content of the link (for <a> tags)
+	Duration      time.Duration // Time taken to validate
+	IsBroken      bool          // Whether the link is broken
+	Soft404       bool          // Returned 2xx but looks like a "not found" page
+	ContentType   string        // Media type reported by the server
+	ContentLength int64         // Size in bytes, -1 if unknown
+	Oversize      bool          // Larger than the configured maximum for its asset kind
+	Flaky         bool          // Broken on the first check but fine when re-verified
+	FirstCheck    string        `json:",omitempty"` // What the first check saw, for flaky links
+	cacheKey      string        // Key the result is cached and stored under
+	finalURL      string        // Where the link ended up after redirects
 }
 
 // ValidationReport contains all validation results
@@ -30,29 +41,74 @@ type ValidationReport struct {
 	BrokenLinks    int
 	WarningLinks   int
 	SuccessLinks   int
+	Soft404Links   int
+	OversizeLinks  int
+	FlakyLinks     int // Broken at first but fine when re-verified
 	ExternalLinks  int
 	InternalLinks  int
 	CachedLinks    int
 	UniqueURLs     int
 	PagesProcessed int
-	CheckExternal  bool          // Whether external links were checked
+	CheckExternal  bool // Whether external links were checked
 	StartTime      time.Time
 	EndTime        time.Time
 	Duration       time.Duration
-	LinksByTag     map[string]int // Count of links by tag type
-	LinksByStatus  map[int]int    // Count of links by status code
+	LinksByTag     map[string]int    // Count of links by tag type
+	LinksByStatus  map[int]int       // Count of links by status code
+	Pages          []PageInfo        `json:"-"`          // Per-page fetch details, in input order
+	SitemapIssues  []sitemap.Issue   `json:",omitempty"` // Child sitemap failures, cycles, limits
+	SitemapAudit   *SitemapAudit     `json:",omitempty"`
+	Hreflang       *HreflangReport   `json:",omitempty"`
+	Security       *SecurityReport   `json:",omitempty"`
+	TLS            *TLSReport        `json:",omitempty"`
+	PageChecks     *PageChecksReport `json:",omitempty"`
+	LinkGraph      *LinkGraph        `json:",omitempty"`
+	BrokenTargets  []BrokenTarget    `json:",omitempty"` // Broken URLs with every occurrence, most widespread first
 }
 
+// PageInfo describes a crawled page itself, as opposed to the links on it
+type PageInfo struct {
+	URL        string
+	StatusCode int
+	FinalURL   string // After redirects
+	NoIndex    bool   // From <meta name="robots"> or X-Robots-Tag
+	Canonical  string // Absolute canonical URL, if declared
+	Alternates []fetcher.Alternate
+	Blocked    bool // Disallowed by robots.txt
+	Error      error
+}
+
+// ErrDisallowedByRobots is returned when robots.txt forbids fetching a page
+var ErrDisallowedByRobots = errors.New("disallowed by robots.txt")
+
 // Validator validates links from pages
 type Validator struct {
-	client        *fetcher.Client
-	concurrency   int
-	verbose       bool
-	showProgress  bool
-	skipResources bool
-	urlCache      map[string]*Result
-	cacheMutex    sync.RWMutex
-	urlMatcher    *URLMatcher
+	client          *fetcher.Client
+	concurrency     int
+	verbose         bool
+	showProgress    bool
+	skipResources   bool
+	skipRels        []string
+	robotsLinks     bool
+	urlCache        map[string]*Result
+	cacheMutex      sync.RWMutex
+	pages           map[string]PageInfo
+	pagesMutex      sync.Mutex
+	state           *State                      // Incremental mode state (nil when disabled)
+	lastMods        map[string]time.Time        // Sitemap lastmod per page URL
+	linkTTL         time.Duration               // How long stored link results stay valid
+	media           map[string][]fetcher.Link   // Sitemap image/video URLs per page
+	soft404         *softNotFoundDetector       // nil unless soft 404 detection is enabled
+	content         *contentChecks              // nil unless content checks are enabled
+	security        *securityAuditor            // nil unless the security audit is enabled
+	pageChecks      *pageChecker                // nil unless on-page checks are enabled
+	tlsCheck        bool                        // Whether certificate details are reported
+	tlsExpiryDays   int                         // Flag certificates expiring within this many days
+	canon           *fetcher.Canonicalizer      // Cache and dedup keys; results keep the original URL
+	duplicates      map[string][]LinkOccurrence // Same-page repeats dropped by FilterLinks, by canonical URL
+	duplicatesMutex sync.Mutex
+	metrics         *runMetrics // nil unless metrics are enabled
+	urlMatcher      *URLMatcher
 }
 
 // NewValidator creates a new link validator
@@ -63,9 +119,26 @@ func NewValidator(timeout, maxRetries, concurrency int, verbose bool) *Validator
 		verbose:      verbose,
 		showProgress: !verbose, // Show progress bar only when not verbose
 		urlCache:     make(map[string]*Result),
+		pages:        make(map[string]PageInfo),
+		canon:        &fetcher.Canonicalizer{},
+		duplicates:   make(map[string][]LinkOccurrence),
 	}
 }
 
+// SetCanonicalization configures how link URLs are normalized for caching and
+// dedup: tracking parameters to ignore and whether /a/ and /a are the same
+func (v *Validator) SetCanonicalization(stripParams []string, foldTrailingSlash bool) {
+	v.canon = &fetcher.Canonicalizer{
+		StripParams:       stripParams,
+		FoldTrailingSlash: foldTrailingSlash,
+	}
+}
+
+// Client returns the HTTP client used for all requests, so callers can share it
+func (v *Validator) Client() *fetcher.Client {
+	return v.client
+}
+
 // SetShowProgress controls whether to show progress bar
 func (v *Validator) SetShowProgress(show bool) {
 	v.showProgress = show
@@ -76,6 +149,34 @@ func (v *Validator) SetSkipResources(skip bool) {
 	v.skipResources = skip
 }
 
+// SetSkipRels sets rel values (e.g. nofollow, sponsored) whose links are not validated
+func (v *Validator) SetSkipRels(rels []string) {
+	v.skipRels = rels
+}
+
+// SetRespectRobots enables robots.txt enforcement for page fetches
+// Crawl-delay directives are applied as per-host rate limits
+func (v *Validator) SetRespectRobots(respect bool) {
+	if respect {
+		v.client.EnableRobots()
+	}
+}
+
+// SetRobotsForLinks controls whether link checks also honor robots.txt
+// Only takes effect when robots.txt enforcement is enabled
+func (v *Validator) SetRobotsForLinks(enabled bool) {
+	v.robotsLinks = enabled
+}
+
+// SetIncremental enables incremental mode: pages whose lastmod is not newer
+// than their last crawl reuse stored links, and link results younger than
+// linkTTL are reused instead of re-checked
+func (v *Validator) SetIncremental(state *State, lastMods map[string]time.Time, linkTTL time.Duration) {
+	v.state = state
+	v.lastMods = lastMods
+	v.linkTTL = linkTTL
+}
+
 // SetExcludePatterns sets URL patterns to exclude from validation
 func (v *Validator) SetExcludePatterns(patterns []string) error {
 	matcher, err := NewURLMatcher(patterns, nil)
@@ -107,31 +208,146 @@ func (v *Validator) validatePageInternal(pageURL string, checkExternal bool, sho
 		fmt.Printf("Fetching page: %s\n", pageURL)
 	}
 
-	// Fetch the page
-	resp := v.client.Get(pageURL)
+	links, err := v.fetchPageLinks(pageURL)
+	if err != nil {
+		return nil, err
+	}
+
+	// Audit all extracted links before filtering
+	if v.security != nil {
+		v.security.auditPage(pageURL, links, checkExternal)
+	}
+
+	// Filter links; rel filtering comes first so a skipped rel=nofollow
+	// copy can't win the de-duplication over a plain copy of the same URL
+	links = fetcher.FilterLinksByRel(links, v.skipRels)
+	extracted := links
+	links = fetcher.FilterLinks(links, checkExternal, v.canon)
+	v.recordDuplicates(pageURL, extracted, links)
+
+	if v.verbose {
+		fmt.Printf("Found %d links to validate\n", len(links))
+	}
+
+	// Validate links concurrently
+	return v.validateLinksInternal(pageURL, links, showProgress), nil
+}
+
+// fetchPageLinks fetches a page, records its details and extracts its links
+// In incremental mode, unchanged pages reuse the links stored in the state
+func (v *Validator) fetchPageLinks(pageURL string) ([]fetcher.Link, error) {
+	if !v.client.Allowed(pageURL) {
+		v.recordPage(PageInfo{URL: pageURL, Blocked: true, Error: ErrDisallowedByRobots})
+		return nil, ErrDisallowedByRobots
+	}
+
+	if stored, ok := v.unchangedPage(pageURL); ok {
+		if v.verbose {
+			fmt.Printf("Unchanged since %s, reusing %d stored links: %s\n", stored.CrawledAt.Format(time.RFC3339), len(stored.Links), pageURL)
+		}
+		v.recordPage(PageInfo{
+			URL:        pageURL,
+			StatusCode: stored.StatusCode,
+			FinalURL:   stored.FinalURL,
+			Canonical:  stored.Canonical,
+			NoIndex:    stored.NoIndex,
+			Alternates: stored.Alternates,
+		})
+		if v.pageChecks != nil && stored.Structure != nil {
+			v.pageChecks.record(pageURL, *stored.Structure)
+		}
+		return stored.Links, nil
+	}
+
+	// Fetch the page (rendered in a browser when a renderer is configured)
+	resp := v.client.Render(pageURL)
+	v.metrics.recordRequest(resp)
+	if v.security != nil {
+		v.security.auditRedirects("sitemap", fetcher.Link{URL: pageURL}, resp)
+	}
+	page := PageInfo{
+		URL:        pageURL,
+		StatusCode: resp.StatusCode,
+		FinalURL:   resp.FinalURL,
+	}
+
 	if resp.Error != nil {
-		return nil, fmt.Errorf("failed to fetch page: %w", resp.Error)
+		page.Error = fmt.Errorf("failed to fetch page: %w", v.describeTLSError(resp))
+		v.recordPage(page)
+		return nil, page.Error
 	}
 
 	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("page returned status %d", resp.StatusCode)
+		page.Error = fmt.Errorf("page returned status %d", resp.StatusCode)
+		v.recordPage(page)
+		return nil, page.Error
 	}
 
-	// Extract links
-	links, err := fetcher.ExtractLinks(resp.Body, pageURL, v.skipResources)
+	// Links, metadata and structure all come from a single parse
+	parsed, err := fetcher.ParsePage(resp.Body, pageURL, v.skipResources)
 	if err != nil {
-		return nil, fmt.Errorf("failed to extract links: %w", err)
+		page.Error = fmt.Errorf("failed to extract links: %w", err)
+		v.recordPage(page)
+		return nil, page.Error
 	}
 
-	// Filter links
-	links = fetcher.FilterLinks(links, checkExternal)
+	// Record page-level metadata (canonical, noindex)
+	page.Canonical = parsed.Meta.Canonical
+	page.NoIndex = parsed.Meta.NoIndex()
+	page.Alternates = parsed.Meta.Alternates
+	if robotsTag := strings.ToLower(resp.Header.Get("X-Robots-Tag")); strings.Contains(robotsTag, "noindex") || (fetcher.PageMeta{Robots: robotsTag}).NoIndex() {
+		page.NoIndex = true
+	}
+	v.recordPage(page)
 
-	if v.verbose {
-		fmt.Printf("Found %d links to validate\n", len(links))
+	// Record on-page structure for HTML/SEO checks
+	var structure *fetcher.PageStructure
+	if v.pageChecks != nil && isHTML(resp.Header.Get("Content-Type")) {
+		structure = &parsed.Structure
+		v.pageChecks.record(pageURL, parsed.Structure)
+	}
+	links := parsed.Links
+
+	if v.state != nil {
+		v.state.setPage(pageURL, &PageState{
+			CrawledAt:  time.Now(),
+			StatusCode: page.StatusCode,
+			FinalURL:   page.FinalURL,
+			Canonical:  page.Canonical,
+			NoIndex:    page.NoIndex,
+			Alternates: page.Alternates,
+			Structure:  structure,
+			Links:      links,
+		})
 	}
 
-	// Validate links concurrently
-	return v.validateLinksInternal(pageURL, links, showProgress), nil
+	return links, nil
+}
+
+// unchangedPage returns the stored state for a page whose sitemap lastmod is
+// not newer than its last crawl; pages without a lastmod are always re-fetched
+func (v *Validator) unchangedPage(pageURL string) (*PageState, bool) {
+	if v.state == nil {
+		return nil, false
+	}
+
+	lastMod, ok := v.lastMods[pageURL]
+	if !ok {
+		return nil, false
+	}
+
+	stored, ok := v.state.page(pageURL)
+	if !ok || lastMod.After(stored.CrawledAt) {
+		return nil, false
+	}
+	return stored, true
+}
+
+// recordPage stores page-level details for the report
+func (v *Validator) recordPage(page PageInfo) {
+	v.pagesMutex.Lock()
+	v.pages[page.URL] = page
+	v.pagesMutex.Unlock()
 }
 
 // validateLinks validates multiple links concurrently
@@ -168,7 +384,7 @@ func (v *Validator) validateLinksInternal(sourceURL string, links []fetcher.Link
 		wg.Add(1)
 		go func(idx int, l fetcher.Link) {
 			defer wg.Done()
-			semaphore <- struct{}{} // Acquire
+			semaphore <- struct{}{}        // Acquire
 			defer func() { <-semaphore }() // Release
 
 			results[idx] = v.validateLink(sourceURL, l)
@@ -207,32 +423,85 @@ func (v *Validator) validateLink(sourceURL string, link fetcher.Link) Result {
 		}
 	}
 
-	// Check cache first
+	// Check robots.txt if enabled for link checks
+	if v.robotsLinks && !v.client.Allowed(link.URL) {
+		return Result{
+			SourceURL:  sourceURL,
+			TargetURL:  link.URL,
+			Status:     "Skipped (disallowed by robots.txt)",
+			IsExternal: link.IsExternal,
+			Tag:        link.Tag,
+			LinkText:   link.Text,
+		}
+	}
+
+	// Check cache first, keyed on the canonical URL and the checks the tag gets
+	key := v.cacheKey(link)
 	v.cacheMutex.RLock()
-	if cached, found := v.urlCache[link.URL]; found {
+	if cached, found := v.urlCache[key]; found {
 		v.cacheMutex.RUnlock()
 		// Return cached result with updated source
 		cachedCopy := *cached
 		cachedCopy.SourceURL = sourceURL
+		cachedCopy.TargetURL = link.URL
 		cachedCopy.Tag = link.Tag
 		cachedCopy.LinkText = link.Text
+		v.metrics.recordCache(true)
 		return cachedCopy
 	}
 	v.cacheMutex.RUnlock()
 
+	// Reuse a stored result from a previous run if it hasn't expired
+	if v.state != nil {
+		if stored, ok := v.state.link(key, v.linkTTL); ok {
+			result := Result{
+				SourceURL:     sourceURL,
+				TargetURL:     link.URL,
+				StatusCode:    stored.StatusCode,
+				Status:        stored.Status,
+				IsExternal:    link.IsExternal,
+				Tag:           link.Tag,
+				LinkText:      link.Text,
+				IsBroken:      stored.IsBroken,
+				Soft404:       stored.Soft404,
+				ContentType:   stored.ContentType,
+				ContentLength: stored.ContentLength,
+				Oversize:      stored.Oversize,
+				cacheKey:      key,
+				finalURL:      stored.FinalURL,
+			}
+			if stored.Error != "" {
+				result.Error = errors.New(stored.Error)
+			}
+
+			v.cacheMutex.Lock()
+			v.urlCache[key] = &result
+			v.cacheMutex.Unlock()
+
+			v.metrics.recordCache(true)
+			return result
+		}
+	}
+
 	// Use HEAD request for efficiency
 	resp := v.client.Head(link.URL)
+	v.metrics.recordCache(false)
+	v.metrics.recordRequest(resp)
 
 	result := Result{
-		SourceURL:  sourceURL,
-		TargetURL:  link.URL,
-		StatusCode: resp.StatusCode,
-		Status:     resp.Status,
-		Error:      resp.Error,
-		IsExternal: link.IsExternal,
-		Tag:        link.Tag,
-		LinkText:   link.Text,
-		Duration:   resp.Duration,
+		SourceURL:     sourceURL,
+		TargetURL:     link.URL,
+		StatusCode:    resp.StatusCode,
+		Status:        resp.Status,
+		Error:         v.describeTLSError(resp),
+		IsExternal:    link.IsExternal,
+		Tag:           link.Tag,
+		LinkText:      link.Text,
+		Duration:      resp.Duration,
+		ContentType:   resp.ContentType,
+		ContentLength: resp.ContentLength,
+		cacheKey:      key,
+		finalURL:      resp.FinalURL,
 	}
 
 	// Determine if link is broken
@@ -245,20 +514,58 @@ func (v *Validator) validateLink(sourceURL string, link fetcher.Link) Result {
 		result.IsBroken = false
 	}
 
+	if v.security != nil {
+		v.security.auditRedirects(sourceURL, link, resp)
+	}
+
+	// Assets must match their tag and stay within size limits
+	if v.content != nil && !result.IsBroken {
+		v.content.apply(link, resp, &result)
+	}
+
+	// Pages that "succeed" may still be error pages in disguise
+	if v.soft404 != nil && !result.IsBroken && link.Tag == "a" && resp.StatusCode >= 200 && resp.StatusCode < 300 && isHTML(resp.Header.Get("Content-Type")) {
+		if reason := v.soft404.check(link.URL); reason != "" {
+			result.IsBroken = true
+			result.Soft404 = true
+			result.Status = "Soft 404: " + reason
+		}
+	}
+
 	// Cache the result
 	v.cacheMutex.Lock()
-	v.urlCache[link.URL] = &result
+	v.urlCache[key] = &result
 	v.cacheMutex.Unlock()
 
+	if v.state != nil {
+		v.state.setLink(key, result)
+	}
+
 	return result
 }
 
+// cacheKey identifies a link's result for caching: its canonical URL, plus
+// the kind of asset when content checks apply and "a" when soft 404 detection
+// does, since those checks can give the same URL different results per tag
+func (v *Validator) cacheKey(link fetcher.Link) string {
+	key := v.canon.Canonical(link.URL)
+	if v.content != nil {
+		if kind := assetKind(link); kind != "" {
+			return key + " " + kind
+		}
+	}
+	if v.soft404 != nil && link.Tag == "a" {
+		return key + " a"
+	}
+	return key
+}
+
 // ValidateMultiplePages validates links from multiple pages
 func (v *Validator) ValidateMultiplePages(pageURLs []string, checkExternal bool) *ValidationReport {
 	report := &ValidationReport{
-		Results:      make([]Result, 0),
-		StartTime:    time.Now(),
-		LinksByTag:   make(map[string]int),
+		Results:       make([]Result, 0),
+		StartTime:     time.Now(),
+		LinksByTag:    make(map[string]int),
 		LinksByStatus: make(map[int]int),
 		CheckExternal: checkExternal,
 	}
@@ -268,11 +575,14 @@ func (v *Validator) ValidateMultiplePages(pageURLs []string, checkExternal bool)
 	}
 
 	report.PagesProcessed = len(pageURLs)
-	uniqueURLs := make(map[string]bool)
+	if v.metrics != nil {
+		v.metrics.pagesTotal.Set(float64(len(pageURLs)))
+	}
 
 	// Process pages concurrently (but limit to reasonable number)
 	type pageResult struct {
 		results []Result
+		media   []Result
 		err     error
 		pageURL string
 		index   int
@@ -303,6 +613,7 @@ func (v *Validator) ValidateMultiplePages(pageURLs []string, checkExternal bool)
 			results, err := v.validatePageInternal(url, checkExternal, false) // No progress bar per page
 			resultsChan <- pageResult{
 				results: results,
+				media:   v.validateMedia(url, checkExternal),
 				err:     err,
 				pageURL: url,
 				index:   idx,
@@ -322,36 +633,113 @@ func (v *Validator) ValidateMultiplePages(pageURLs []string, checkExternal bool)
 
 	// Collect results
 	for pr := range resultsChan {
+		// Sitemap media is validated even if the page itself failed
+		report.Results = append(report.Results, pr.media...)
+		for _, result := range pr.media {
+			v.metrics.recordResult(result)
+		}
+
+		if errors.Is(pr.err, ErrDisallowedByRobots) {
+			if v.verbose {
+				fmt.Printf("  Skipping %s: %v\n", pr.pageURL, pr.err)
+			}
+			report.Results = append(report.Results, Result{
+				SourceURL: "sitemap",
+				TargetURL: pr.pageURL,
+				Status:    "Skipped (disallowed by robots.txt)",
+			})
+			v.metrics.recordPage("skipped")
+			continue
+		}
+
 		if pr.err != nil {
 			if v.verbose {
 				fmt.Printf("  ⚠ Error validating page: %v\n", pr.err)
 			}
 			// Create a result for the page itself
-			report.Results = append(report.Results, Result{
+			failed := Result{
 				SourceURL:  "sitemap",
 				TargetURL:  pr.pageURL,
 				StatusCode: 0,
 				Status:     "Failed",
 				Error:      pr.err,
 				IsBroken:   true,
-			})
+			}
+			report.Results = append(report.Results, failed)
+			if v.metrics != nil {
+				v.pagesMutex.Lock()
+				statusCode := v.pages[pr.pageURL].StatusCode
+				v.pagesMutex.Unlock()
+				v.metrics.recordFailedPage(failed, statusCode)
+			}
 			continue
 		}
 
 		report.Results = append(report.Results, pr.results...)
+		for _, result := range pr.results {
+			v.metrics.recordResult(result)
+		}
+		v.metrics.recordPage("ok")
 	}
 
 	report.EndTime = time.Now()
 	report.Duration = report.EndTime.Sub(report.StartTime)
 
-	// Calculate statistics
+	v.calculateStats(report)
+
+	if v.security != nil {
+		report.Security = v.security.report()
+	}
+	if v.tlsCheck {
+		report.TLS = v.tlsReport()
+	}
+	if v.pageChecks != nil {
+		report.PageChecks = v.pageChecks.report()
+	}
+
+	// Attach page details in input order
+	v.pagesMutex.Lock()
+	for _, pageURL := range pageURLs {
+		if page, ok := v.pages[pageURL]; ok {
+			report.Pages = append(report.Pages, page)
+		}
+	}
+	v.pagesMutex.Unlock()
+
+	// Track cached links
+	v.cacheMutex.RLock()
+	report.CachedLinks = len(v.urlCache)
+	v.cacheMutex.RUnlock()
+
+	return report
+}
+
+// calculateStats computes the report's counts and broken-target groups from
+// its results, replacing any previous values
+func (v *Validator) calculateStats(report *ValidationReport) {
+	report.TotalLinks, report.BrokenLinks, report.WarningLinks, report.SuccessLinks = 0, 0, 0, 0
+	report.Soft404Links, report.OversizeLinks, report.FlakyLinks = 0, 0, 0
+	report.ExternalLinks, report.InternalLinks = 0, 0
+	report.LinksByTag = make(map[string]int)
+	report.LinksByStatus = make(map[int]int)
+	uniqueURLs := make(map[string]bool)
+
 	for _, result := range report.Results {
 		report.TotalLinks++
 
 		// Track unique URLs
-		uniqueURLs[result.TargetURL] = true
+		uniqueURLs[v.canon.Canonical(result.TargetURL)] = true
 
 		// Categorize by status
+		if result.Soft404 {
+			report.Soft404Links++
+		}
+		if result.Oversize {
+			report.OversizeLinks++
+		}
+		if result.Flaky {
+			report.FlakyLinks++
+		}
 		if result.IsBroken {
 			report.BrokenLinks++
 		} else if result.StatusCode >= 300 && result.StatusCode < 400 {
@@ -378,13 +766,6 @@ func (v *Validator) ValidateMultiplePages(pageURLs []string, checkExternal bool)
 		}
 	}
 
-	// Track unique URLs count
 	report.UniqueURLs = len(uniqueURLs)
-
-	// Track cached links
-	v.cacheMutex.RLock()
-	report.CachedLinks = len(v.urlCache)
-	v.cacheMutex.RUnlock()
-
-	return report
+	report.BrokenTargets = v.groupBrokenTargets(report.Results)
 }
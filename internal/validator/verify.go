@@ -0,0 +1,131 @@
+package validator
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"linkchex/internal/fetcher"
+)
+
+// VerifyOptions controls the re-verification pass over broken links
+type VerifyOptions struct {
+	Delay   time.Duration // Wait before re-checking, to let transient failures clear
+	UseGET  bool          // Re-check with GET instead of HEAD
+	Timeout time.Duration // Request timeout for re-checks (0 = same as the first pass)
+}
+
+// VerifyBrokenLinks re-checks every broken link after a delay and marks the
+// ones that now work as flaky instead of broken, then recalculates the
+// report's statistics. Soft 404s and content-type failures are not
+// re-checked since they come from a successful response, and neither are
+// pages that failed to crawl
+// Returns the number of links marked flaky, counted like FlakyLinks
+func (v *Validator) VerifyBrokenLinks(report *ValidationReport, opts VerifyOptions) int {
+	// Re-check each canonical target once
+	var targets []string
+	seen := make(map[string]bool)
+	for _, result := range report.Results {
+		if !needsVerification(result) {
+			continue
+		}
+		key := v.canon.Canonical(result.TargetURL)
+		if !seen[key] {
+			seen[key] = true
+			targets = append(targets, result.TargetURL)
+		}
+	}
+	if len(targets) == 0 {
+		return 0
+	}
+
+	if v.verbose {
+		fmt.Printf("Re-verifying %d broken link(s) in %s...\n", len(targets), opts.Delay)
+	}
+	time.Sleep(opts.Delay)
+
+	client := v.client.WithTimeout(opts.Timeout)
+	recovered := make(map[string]*fetcher.Response)
+	var recoveredMutex sync.Mutex
+
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, v.concurrency)
+	for _, target := range targets {
+		wg.Add(1)
+		go func(target string) {
+			defer wg.Done()
+			semaphore <- struct{}{}        // Acquire
+			defer func() { <-semaphore }() // Release
+
+			var resp *fetcher.Response
+			if opts.UseGET {
+				resp = client.Get(target)
+			} else {
+				resp = client.Head(target)
+			}
+			v.metrics.recordRequest(resp)
+
+			if resp.Error == nil && resp.StatusCode < 400 {
+				recoveredMutex.Lock()
+				recovered[v.canon.Canonical(target)] = resp
+				recoveredMutex.Unlock()
+			}
+		}(target)
+	}
+	wg.Wait()
+
+	// Replace the broken results of recovered targets, everywhere they occur
+	flaky := 0
+	for i := range report.Results {
+		result := &report.Results[i]
+		if !needsVerification(*result) {
+			continue
+		}
+		key := v.canon.Canonical(result.TargetURL)
+		resp, ok := recovered[key]
+		if !ok {
+			continue
+		}
+
+		result.FirstCheck = targetStatus(result.StatusCode, result.Status, errorText(result.Error))
+		result.Flaky = true
+		result.IsBroken = false
+		result.Error = nil
+		result.StatusCode = resp.StatusCode
+		result.Status = resp.Status
+		result.Duration = resp.Duration
+		result.ContentType = resp.ContentType
+		result.ContentLength = resp.ContentLength
+		result.finalURL = resp.FinalURL
+		v.metrics.recordFlaky(*result)
+		flaky++
+
+		// Later lookups and incremental runs should see the working result
+		if result.cacheKey == "" {
+			continue
+		}
+		v.cacheMutex.Lock()
+		cached := *result
+		v.urlCache[result.cacheKey] = &cached
+		v.cacheMutex.Unlock()
+		if v.state != nil {
+			v.state.setLink(result.cacheKey, *result)
+		}
+	}
+
+	report.EndTime = time.Now()
+	report.Duration = report.EndTime.Sub(report.StartTime)
+	v.calculateStats(report)
+
+	return flaky
+}
+
+// needsVerification reports whether a link failed in a way a retry might fix
+// Pages that failed to crawl are skipped; a working re-check wouldn't
+// recover the links that were never extracted from them
+func needsVerification(result Result) bool {
+	if result.SourceURL == "sitemap" {
+		return false
+	}
+	return result.IsBroken && !result.Soft404 && (result.Error != nil || result.StatusCode >= 400)
+}